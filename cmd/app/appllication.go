@@ -3,6 +3,7 @@ package app
 import (
 	"PR-appointer/config"
 	"PR-appointer/internal/router"
+	"PR-appointer/internal/service"
 	"PR-appointer/internal/storage"
 	"context"
 	"errors"
@@ -23,9 +24,26 @@ func StartApplication(ctx context.Context) error {
 
 	slog.Info("starting application")
 
-	cfg.Client = storage.NewConnection(ctx, cfg)
+	// Без аутентификации любой вызывающий получает все права, поэтому это допустимо только при явном AUTH_ENABLED=false
+	if !cfg.Env.AuthEnabled {
+		slog.Warn("authentication is disabled (AUTH_ENABLED=false), every caller has full access")
+	} else if cfg.Env.DBDriver == storage.DriverMemory {
+		return errors.New("in-memory storage cannot issue API tokens: set AUTH_ENABLED=false to run it without authentication")
+	}
+
+	store, err := storage.NewStore(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	r, err := router.SetupRouter(ctx, cfg, store)
+	if err != nil {
+		return err
+	}
 
-	r := router.SetupRouter(ctx, cfg.Client)
+	// События из outbox и исходящие вебхуки обрабатываются в фоне до остановки сервиса
+	go service.NewOutboxDispatcher(store, service.NewWebhookSink(store)).Run(ctx)
+	go service.NewWebhookDispatcher(store).Run(ctx)
 
 	addr := fmt.Sprintf("%s:%d", cfg.Env.IPAddress, cfg.Env.APIPort)
 	server := &http.Server{
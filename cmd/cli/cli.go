@@ -0,0 +1,357 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"PR-appointer/config"
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/service"
+	"PR-appointer/internal/storage"
+)
+
+const usage = `Usage: pr-appointer <command> [arguments]
+
+Commands:
+  serve                                  start the HTTP server (default)
+  migrate up                             apply pending migrations
+  migrate down [-steps N]                roll back the last N migrations (default 1)
+  migrate status                         list migrations and when they were applied
+  seed                                   load demo teams and users
+  team import -file teams.json           create teams from a JSON file ("-" for stdin)
+  user deactivate [-ids 1,2] [-team T]   deactivate users and reassign their open reviews
+  pr reassign -pr ID -reviewer ID        replace a reviewer on an open PR
+  token create -name N -role R [-user U] issue an API token (admin, team-lead, member, bot)
+  token revoke -id ID                    revoke an API token
+
+The storage is selected with the same environment variables as the server (DB_DRIVER, DB_*).
+`
+
+// ErrUsage - команда вызвана с неверными аргументами.
+var ErrUsage = errors.New("invalid usage")
+
+// Run выполняет служебную команду через тот же сервисный слой, что и HTTP API.
+// Результат печатается в stdout в JSON, чтобы его было удобно разбирать в скриптах.
+func Run(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return usageError("command is required")
+	}
+
+	cfg := config.GetConfig()
+	// Изменения из командной строки записываются в журнал аудита на cli
+	ctx = service.WithActor(ctx, "cli")
+
+	switch args[0] {
+	case "migrate":
+		return runMigrate(ctx, cfg, args[1:], stdout)
+	case "seed":
+		return withDatabase(ctx, cfg, func(db *storage.Database) error {
+			if err := db.Seed(ctx); err != nil {
+				return err
+			}
+			return writeJSON(stdout, map[string]string{"status": "seeded"})
+		})
+	case "team":
+		return runTeam(ctx, cfg, args[1:], stdin, stdout)
+	case "user":
+		return runUser(ctx, cfg, args[1:], stdout)
+	case "pr":
+		return runPR(ctx, cfg, args[1:], stdout)
+	case "token":
+		return runToken(ctx, cfg, args[1:], stdout)
+	case "help", "-h", "--help":
+		_, err := fmt.Fprint(stdout, usage)
+		return err
+	default:
+		return usageError("unknown command: " + args[0])
+	}
+}
+
+func runMigrate(ctx context.Context, cfg *config.Config, args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return usageError("migrate: up, down or status is required")
+	}
+
+	return withDatabase(ctx, cfg, func(db *storage.Database) error {
+		if db.Migrator == nil {
+			return fmt.Errorf("migrations are not supported for DB_DRIVER=%s", cfg.Env.DBDriver)
+		}
+
+		switch args[0] {
+		case "up":
+			applied, err := db.Migrator.Up(ctx)
+			if err != nil {
+				return err
+			}
+			return writeJSON(stdout, map[string]any{"applied": migrationNames(applied)})
+		case "down":
+			fs := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+			steps := fs.Int("steps", 1, "number of migrations to roll back")
+			if err := fs.Parse(args[1:]); err != nil {
+				return usageError(err.Error())
+			}
+			if *steps < 1 {
+				return usageError("migrate down: -steps must be positive")
+			}
+
+			rolledBack, err := db.Migrator.Down(ctx, *steps)
+			if err != nil {
+				return err
+			}
+			return writeJSON(stdout, map[string]any{"rolled_back": migrationNames(rolledBack)})
+		case "status":
+			statuses, err := db.Migrator.Status(ctx)
+			if err != nil {
+				return err
+			}
+
+			type migrationStatus struct {
+				Version   int        `json:"version"`
+				Name      string     `json:"name"`
+				AppliedAt *time.Time `json:"applied_at"`
+			}
+			result := make([]migrationStatus, 0, len(statuses))
+			for _, s := range statuses {
+				result = append(result, migrationStatus{Version: s.Version, Name: s.Name, AppliedAt: s.AppliedAt})
+			}
+			return writeJSON(stdout, result)
+		default:
+			return usageError("migrate: unknown subcommand: " + args[0])
+		}
+	})
+}
+
+func runTeam(ctx context.Context, cfg *config.Config, args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 || args[0] != "import" {
+		return usageError("team: import is required")
+	}
+
+	fs := flag.NewFlagSet("team import", flag.ContinueOnError)
+	file := fs.String("file", "", `JSON file with a team or a list of teams ("-" for stdin)`)
+	if err := fs.Parse(args[1:]); err != nil {
+		return usageError(err.Error())
+	}
+	if *file == "" {
+		return usageError("team import: -file is required")
+	}
+
+	var data []byte
+	var err error
+	if *file == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(*file)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read teams: %w", err)
+	}
+
+	teams, err := parseTeams(data)
+	if err != nil {
+		return err
+	}
+
+	return withDatabase(ctx, cfg, func(db *storage.Database) error {
+		teamService := service.NewTeamService(db.Store)
+
+		var created []*entity.TeamResponse
+		for _, team := range teams {
+			resp, err := teamService.CreateTeam(ctx, &team)
+			if err != nil {
+				return fmt.Errorf("team %q: %w", team.TeamName, err)
+			}
+			created = append(created, resp)
+		}
+
+		return writeJSON(stdout, map[string]any{"teams": created})
+	})
+}
+
+// parseTeams принимает как одну команду в формате /team/add, так и их список.
+func parseTeams(data []byte) ([]entity.TeamCreateRequest, error) {
+	data = bytes.TrimSpace(data)
+
+	var teams []entity.TeamCreateRequest
+	if bytes.HasPrefix(data, []byte("[")) {
+		if err := json.Unmarshal(data, &teams); err != nil {
+			return nil, fmt.Errorf("failed to parse teams: %w", err)
+		}
+	} else {
+		var team entity.TeamCreateRequest
+		if err := json.Unmarshal(data, &team); err != nil {
+			return nil, fmt.Errorf("failed to parse team: %w", err)
+		}
+		teams = append(teams, team)
+	}
+
+	for _, team := range teams {
+		if team.TeamName == "" {
+			return nil, errors.New("team_name is required for every team")
+		}
+	}
+
+	return teams, nil
+}
+
+func runUser(ctx context.Context, cfg *config.Config, args []string, stdout io.Writer) error {
+	if len(args) == 0 || args[0] != "deactivate" {
+		return usageError("user: deactivate is required")
+	}
+
+	fs := flag.NewFlagSet("user deactivate", flag.ContinueOnError)
+	ids := fs.String("ids", "", "comma-separated user ids")
+	team := fs.String("team", "", "deactivate all members of the team")
+	if err := fs.Parse(args[1:]); err != nil {
+		return usageError(err.Error())
+	}
+
+	userIDs, err := parseIDs(*ids)
+	if err != nil {
+		return usageError("user deactivate: " + err.Error())
+	}
+	if len(userIDs) == 0 && *team == "" {
+		return usageError("user deactivate: -ids or -team is required")
+	}
+
+	return withDatabase(ctx, cfg, func(db *storage.Database) error {
+		resp, err := service.NewUserService(db.Store, seedSource(cfg)).BulkDeactivate(ctx, &entity.BulkDeactivateRequest{
+			UserIDs:  userIDs,
+			TeamName: *team,
+		})
+		if err != nil {
+			return err
+		}
+		return writeJSON(stdout, resp)
+	})
+}
+
+func runPR(ctx context.Context, cfg *config.Config, args []string, stdout io.Writer) error {
+	if len(args) == 0 || args[0] != "reassign" {
+		return usageError("pr: reassign is required")
+	}
+
+	fs := flag.NewFlagSet("pr reassign", flag.ContinueOnError)
+	prID := fs.Int("pr", 0, "pull request id")
+	reviewerID := fs.Int("reviewer", 0, "id of the reviewer to replace")
+	if err := fs.Parse(args[1:]); err != nil {
+		return usageError(err.Error())
+	}
+	if *prID == 0 || *reviewerID == 0 {
+		return usageError("pr reassign: -pr and -reviewer are required")
+	}
+
+	return withDatabase(ctx, cfg, func(db *storage.Database) error {
+		pr, replacedBy, err := service.NewPRService(db.Store, seedSource(cfg)).ReassignReviewer(ctx, *prID, *reviewerID)
+		if err != nil {
+			return err
+		}
+		return writeJSON(stdout, map[string]any{"pr": pr, "replaced_by": replacedBy})
+	})
+}
+
+func runToken(ctx context.Context, cfg *config.Config, args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return usageError("token: create or revoke is required")
+	}
+
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("token create", flag.ContinueOnError)
+		name := fs.String("name", "", "token name, e.g. the owner or the integration")
+		role := fs.String("role", "", "admin, team-lead, member or bot")
+		user := fs.String("user", "", "username the token acts for (optional for bots)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return usageError(err.Error())
+		}
+		if *name == "" || *role == "" {
+			return usageError("token create: -name and -role are required")
+		}
+
+		return withDatabase(ctx, cfg, func(db *storage.Database) error {
+			token, err := service.NewAuthService(db.Store, nil).IssueToken(ctx, *name, *role, *user)
+			if err != nil {
+				return err
+			}
+			return writeJSON(stdout, token)
+		})
+	case "revoke":
+		fs := flag.NewFlagSet("token revoke", flag.ContinueOnError)
+		id := fs.Int("id", 0, "token id")
+		if err := fs.Parse(args[1:]); err != nil {
+			return usageError(err.Error())
+		}
+		if *id == 0 {
+			return usageError("token revoke: -id is required")
+		}
+
+		return withDatabase(ctx, cfg, func(db *storage.Database) error {
+			if err := service.NewAuthService(db.Store, nil).RevokeToken(ctx, *id); err != nil {
+				return err
+			}
+			return writeJSON(stdout, map[string]any{"revoked": *id})
+		})
+	default:
+		return usageError("token: unknown subcommand: " + args[0])
+	}
+}
+
+// seedSource - источник зерен выбора ревьюверов с теми же настройками, что и у сервера.
+func seedSource(cfg *config.Config) *service.SeedSource {
+	return service.NewSeedSource(service.SeedConfig{
+		Seed:  cfg.Env.AssignmentSeed,
+		PerPR: cfg.Env.AssignmentSeedPerPR,
+	})
+}
+
+func withDatabase(ctx context.Context, cfg *config.Config, fn func(db *storage.Database) error) error {
+	db, err := storage.Open(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return fn(db)
+}
+
+func parseIDs(value string) ([]int, error) {
+	var ids []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user id %q", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func migrationNames(migrations []storage.Migration) []string {
+	names := make([]string, 0, len(migrations))
+	for _, m := range migrations {
+		names = append(names, fmt.Sprintf("%04d_%s", m.Version, m.Name))
+	}
+	return names
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func usageError(message string) error {
+	return fmt.Errorf("%w: %s\n\n%s", ErrUsage, message, usage)
+}
@@ -9,13 +9,40 @@ import (
 )
 
 type Env struct {
+	// Хранилище: postgres (по умолчанию), sqlite или memory - данные в памяти, без базы
+	DBDriver string `env:"DB_DRIVER" envDefault:"postgres"`
+	// Путь к файлу базы для DB_DRIVER=sqlite
+	DBPath     string `env:"DB_PATH" envDefault:"pr-appointer.db"`
 	DBName     string `env:"DB_NAME"`
 	DBUsername string `env:"DB_USERNAME"`
 	DBPassword string `env:"DB_PASSWORD"`
 	DBPort     int    `env:"DB_PORT"`
 	DBHost     string `env:"DB_HOST"`
-	IPAddress  string `env:"IP_ADDRESS"`
-	APIPort    int    `env:"API_PORT"`
+	// Загружать демонстрационные команды и пользователей при старте
+	SeedData bool `env:"SEED_DATA" envDefault:"false"`
+	// Требовать API-токен (Authorization: Bearer) на всех маршрутах API; false - только для локальной разработки
+	AuthEnabled bool `env:"AUTH_ENABLED" envDefault:"true"`
+	// Файл с ключами проверки JWT от SSO: JWKS или PEM. Пусто - принимаются только API-токены
+	JWTKeysFile string `env:"JWT_KEYS_FILE"`
+	// Claim JWT, значение которого совпадает с users.username
+	JWTUsernameClaim string `env:"JWT_USERNAME_CLAIM" envDefault:"preferred_username"`
+	// Claim JWT с ролью сервиса; пусто - пользователи SSO получают роль member
+	JWTRoleClaim string `env:"JWT_ROLE_CLAIM"`
+	JWTIssuer    string `env:"JWT_ISSUER"`
+	JWTAudience  string `env:"JWT_AUDIENCE"`
+
+	// Секрет вебхука GitHub (проверка X-Hub-Signature-256); пусто - /webhooks/github не принимает события
+	GitHubWebhookSecret string `env:"GITHUB_WEBHOOK_SECRET"`
+	// Секретный токен вебхука GitLab (X-Gitlab-Token); пусто - /webhooks/gitlab не принимает события
+	GitLabWebhookToken string `env:"GITLAB_WEBHOOK_TOKEN"`
+
+	// Зерно случайного выбора ревьюверов; 0 - случайное при каждом запуске
+	AssignmentSeed int64 `env:"ASSIGNMENT_SEED" envDefault:"0"`
+	// Выводить зерно выбора из id PR: повторный выбор для PR с теми же кандидатами дает тот же результат
+	AssignmentSeedPerPR bool `env:"ASSIGNMENT_SEED_PER_PR" envDefault:"false"`
+
+	IPAddress string `env:"IP_ADDRESS"`
+	APIPort   int    `env:"API_PORT"`
 
 	Environment string `env:"ENVIRONMENT"`
 }
@@ -23,6 +23,93 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/audit": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get assignment, reassignment, PR status, user status and team membership changes, newest first. Each entry has the actor, before and after state and, for assignments, the reason the reviewer was picked or removed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Get audit log",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "PR ID",
+                        "name": "pull_request_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Team name",
+                        "name": "team_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the time range, RFC 3339, inclusive",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the time range, RFC 3339, exclusive",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries, 100 by default, up to 500",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.AuditListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            }
+        },
         "/health": {
             "get": {
                 "description": "Check service health status",
@@ -46,9 +133,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/pullRequest/create": {
+        "/pullRequest/close": {
             "post": {
-                "description": "Create PR and automatically assign up to 2 reviewers from author's team",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move PR from DRAFT or OPEN to CLOSED and release its reviewers",
                 "consumes": [
                     "application/json"
                 ],
@@ -58,25 +150,37 @@ const docTemplate = `{
                 "tags": [
                     "PullRequests"
                 ],
-                "summary": "Create PR with auto-assigned reviewers",
+                "summary": "Close PR without merge",
                 "parameters": [
                     {
-                        "description": "PR data",
+                        "description": "PR ID",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.PRCreateRequest"
+                            "$ref": "#/definitions/entity.UpdatePRStatusRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/entity.PRDetailResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -92,9 +196,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/pullRequest/merge": {
+        "/pullRequest/create": {
             "post": {
-                "description": "Set PR status to MERGED (idempotent operation)",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create PR and automatically assign reviewers from author's team according to team settings. Draft PRs get reviewers when marked ready",
                 "consumes": [
                     "application/json"
                 ],
@@ -104,63 +213,41 @@ const docTemplate = `{
                 "tags": [
                     "PullRequests"
                 ],
-                "summary": "Mark PR as merged",
+                "summary": "Create PR with auto-assigned reviewers",
                 "parameters": [
                     {
-                        "description": "PR ID",
+                        "description": "PR data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.UpdatePRStatusRequest"
+                            "$ref": "#/definitions/entity.PRCreateRequest"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include reviewer selection explanation",
+                        "name": "explain",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/entity.MergedPRResponse"
+                            "$ref": "#/definitions/entity.PRDetailResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handler.APIError"
                         }
-                    }
-                }
-            }
-        },
-        "/pullRequest/reassign": {
-            "post": {
-                "description": "Replace one reviewer with another from same team",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "PullRequests"
-                ],
-                "summary": "Reassign reviewer",
-                "parameters": [
-                    {
-                        "description": "Reassignment data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.ReassignReviewerRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/entity.ReassignHandlerResponse"
+                            "$ref": "#/definitions/handler.APIError"
                         }
                     },
                     "404": {
@@ -178,35 +265,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/team/add": {
-            "post": {
-                "description": "Create team and create/update users in it",
-                "consumes": [
-                    "application/json"
+        "/pullRequest/get": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Get PR by ID with its reviewers, the time each reviewer was assigned and the merge time",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Teams"
+                    "PullRequests"
                 ],
-                "summary": "Create team with members",
+                "summary": "Get PR with reviewers",
                 "parameters": [
                     {
-                        "description": "Team data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.TeamCreateRequest"
-                        }
+                        "type": "integer",
+                        "description": "PR ID",
+                        "name": "pull_request_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.TeamResponse"
+                            "$ref": "#/definitions/entity.PRDetailResponse"
                         }
                     },
                     "400": {
@@ -214,34 +301,134 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handler.APIError"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
                     }
                 }
             }
         },
-        "/team/get": {
+        "/pullRequest/list": {
             "get": {
-                "description": "Get team by name",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List PRs with filters, newest first by default. Pages are fetched with next_cursor from the previous response",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Teams"
+                    "PullRequests"
                 ],
-                "summary": "Get team with members",
+                "summary": "List pull requests",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Team name",
+                        "description": "PR status: DRAFT, OPEN, MERGED or CLOSED",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Author ID",
+                        "name": "author_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of a currently assigned reviewer",
+                        "name": "reviewer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "PR team name",
                         "name": "team_name",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive title substring",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after, RFC 3339",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before, RFC 3339",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Merged at or after, RFC 3339",
+                        "name": "merged_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Merged before, RFC 3339",
+                        "name": "merged_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field: created_at (default), updated_at, title or id",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: desc (default) or asc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default, up to 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page, valid only with the same sort and order",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.TeamResponse"
+                            "$ref": "#/definitions/entity.PRListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
                         }
                     },
                     "404": {
@@ -253,38 +440,1150 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/getReview": {
-            "get": {
-                "description": "Get list of PRs where user is assigned as reviewer",
+        "/pullRequest/merge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set PR status to MERGED (idempotent operation). Blocked until the team's required approvals are reached",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "PullRequests"
                 ],
-                "summary": "Get PRs assigned to user as reviewer",
+                "summary": "Mark PR as merged",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "query",
-                        "required": true
+                        "description": "PR ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UpdatePRStatusRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.UserReviewsResponse"
+                            "$ref": "#/definitions/entity.MergedPRResponse"
                         }
-                    }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/preview": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Run reviewer selection for a would-be PR without persisting anything and explain the choice",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Preview reviewer assignment",
+                "parameters": [
+                    {
+                        "description": "PR data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.PRCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PRPreviewResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/ready": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move PR from DRAFT to OPEN and assign reviewers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Mark draft PR as ready for review",
+                "parameters": [
+                    {
+                        "description": "PR ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UpdatePRStatusRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include reviewer selection explanation",
+                        "name": "explain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PRDetailResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/reassign": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace one reviewer with another from same team (or from its fallback teams)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Reassign reviewer",
+                "parameters": [
+                    {
+                        "description": "Reassignment data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReassignReviewerRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include reviewer selection explanation",
+                        "name": "explain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReassignHandlerResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/reopen": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move PR from CLOSED to OPEN and assign reviewers again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Reopen closed PR",
+                "parameters": [
+                    {
+                        "description": "PR ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UpdatePRStatusRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include reviewer selection explanation",
+                        "name": "explain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PRDetailResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/review": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reviewer submits a verdict (APPROVED, CHANGES_REQUESTED, COMMENTED) for the PR",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Submit review verdict",
+                "parameters": [
+                    {
+                        "description": "Review data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.SubmitReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PRDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/add": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Register a URL that receives signed POST requests on pr.created, reviewer.assigned, reviewer.replaced, pr.merged, pr.ready, pr.closed, pr.reopened and user.status_changed events. An empty events list subscribes to all events. The body is signed with HMAC-SHA256 of the secret in X-Appointer-Signature-256",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Subscribe to outbound webhooks",
+                "parameters": [
+                    {
+                        "description": "Subscription data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.SubscriptionCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WebhookSubscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List recent deliveries, newest first, with attempts, last response status and error",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "List outbound webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "subscription_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery status: pending, succeeded or failed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of deliveries, up to 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List subscriptions; secrets are not returned",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "List outbound webhook subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.WebhookSubscription"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/redeliver": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queue a delivery again with a fresh attempt counter, e.g. after a failed delivery was fixed on the receiving side",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Replay outbound webhook delivery",
+                "parameters": [
+                    {
+                        "description": "Delivery ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.DeliveryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/remove": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove subscription together with its delivery log",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Remove outbound webhook subscription",
+                "parameters": [
+                    {
+                        "description": "Subscription ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.SubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/add": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create team and create/update users in it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Create team with members",
+                "parameters": [
+                    {
+                        "description": "Team data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.TeamCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TeamResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/get": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get team by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Get team with members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Team name",
+                        "name": "team_name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TeamResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/settings": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get reviewer assignment settings of the team",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Get team settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Team name",
+                        "name": "team_name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TeamSettingsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update reviewer strategy, reviewers per PR, minimum reviewers, behavior when there are not enough candidates and fallback teams",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Update team settings",
+                "parameters": [
+                    {
+                        "description": "Team settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.TeamSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TeamSettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/bulkDeactivate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deactivate a list of users and/or all members of a team and, in one transaction, reassign their reviewer slots on OPEN PRs. Returns what moved and what could not be filled",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Deactivate users and reassign their open reviews",
+                "parameters": [
+                    {
+                        "description": "Users to deactivate",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.BulkDeactivateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.BulkDeactivateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/getReview": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get list of PRs where user is assigned as reviewer. Without user_id (or with user_id=me) returns the caller's reviews",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get PRs assigned to user as reviewer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, or me for the caller",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserReviewsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/setIsActive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update user's is_active flag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Set user active status",
+                "parameters": [
+                    {
+                        "description": "User active status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/github": {
+            "post": {
+                "description": "Verifies X-Hub-Signature-256 and applies opened, ready_for_review, closed (merged or not) and reopened events to the PR linked to the GitHub repository and number. The PR author is the user whose username equals the GitHub login",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Receive GitHub pull_request webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event type",
+                        "name": "X-GitHub-Event",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "HMAC-SHA256 of the body",
+                        "name": "X-Hub-Signature-256",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WebhookResult"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WebhookResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    }
                 }
             }
         },
-        "/users/setIsActive": {
+        "/webhooks/gitlab": {
             "post": {
-                "description": "Update user's is_active flag",
+                "description": "Verifies X-Gitlab-Token and applies Merge Request Hook events (open, draft removed, merge, close, reopen) to the PR linked to the GitLab project and MR iid. The PR author is the user whose username equals the GitLab username of whoever opened the MR",
                 "consumes": [
                     "application/json"
                 ],
@@ -292,25 +1591,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Webhooks"
                 ],
-                "summary": "Set user active status",
+                "summary": "Receive GitLab merge request webhook",
                 "parameters": [
                     {
-                        "description": "User active status",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.UserRequest"
-                        }
+                        "type": "string",
+                        "description": "Event type",
+                        "name": "X-Gitlab-Event",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook secret token",
+                        "name": "X-Gitlab-Token",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.UserResponse"
+                            "$ref": "#/definitions/entity.WebhookResult"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WebhookResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
                         }
                     },
                     "404": {
@@ -318,18 +1640,259 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handler.APIError"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.APIError"
+                        }
                     }
                 }
             }
         }
     },
     "definitions": {
+        "entity.AssignmentExplanation": {
+            "type": "object",
+            "properties": {
+                "degraded": {
+                    "type": "boolean"
+                },
+                "min_reviewers": {
+                    "type": "integer"
+                },
+                "pools": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.CandidatePool"
+                    }
+                },
+                "reviewers_per_pr": {
+                    "type": "integer"
+                },
+                "seed": {
+                    "type": "integer"
+                },
+                "strategy": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "before": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "pull_request_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "team_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.AuditListResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.AuditEntry"
+                    }
+                }
+            }
+        },
+        "entity.BulkDeactivateRequest": {
+            "type": "object",
+            "properties": {
+                "team_name": {
+                    "type": "string"
+                },
+                "user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "entity.BulkDeactivateResponse": {
+            "type": "object",
+            "properties": {
+                "deactivated": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.UserResponse"
+                    }
+                },
+                "reassigned": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ReassignmentResult"
+                    }
+                },
+                "unfilled": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ReassignmentResult"
+                    }
+                }
+            }
+        },
+        "entity.CandidatePool": {
+            "type": "object",
+            "properties": {
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "excluded": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ExcludedCandidate"
+                    }
+                },
+                "fallback": {
+                    "type": "boolean"
+                },
+                "needed": {
+                    "type": "integer"
+                },
+                "selected": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "strategy": {
+                    "type": "string"
+                },
+                "teams": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "entity.DeliveryRequest": {
+            "type": "object",
+            "required": [
+                "delivery_id"
+            ],
+            "properties": {
+                "delivery_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ExcludedCandidate": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
         "entity.MergedPRResponse": {
             "type": "object",
             "properties": {
                 "author": {
                     "$ref": "#/definitions/entity.UserResponse"
                 },
+                "merged_at": {
+                    "$ref": "#/definitions/sql.NullTime"
+                },
+                "pull_request_id": {
+                    "type": "integer"
+                },
+                "pull_request_name": {
+                    "type": "string"
+                },
+                "reviewers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ReviewerResponse"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.PRCreateRequest": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "integer"
+                },
+                "draft": {
+                    "type": "boolean"
+                },
+                "pull_request_id": {
+                    "type": "integer"
+                },
+                "pull_request_name": {
+                    "type": "string"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.PRDetailResponse": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/entity.UserResponse"
+                },
+                "degraded": {
+                    "type": "boolean"
+                },
+                "explain": {
+                    "description": "Explain - как выбраны ревьюверы; заполняется операциями, которые назначают ревьюверов",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.AssignmentExplanation"
+                        }
+                    ]
+                },
                 "merged_at": {
                     "type": "string"
                 },
@@ -342,7 +1905,7 @@ const docTemplate = `{
                 "reviewers": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/entity.UserResponse"
+                        "$ref": "#/definitions/entity.ReviewerResponse"
                     }
                 },
                 "status": {
@@ -350,40 +1913,72 @@ const docTemplate = `{
                 }
             }
         },
-        "entity.PRCreateRequest": {
+        "entity.PRListItem": {
             "type": "object",
             "properties": {
                 "author_id": {
                     "type": "integer"
                 },
+                "closed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "degraded": {
+                    "type": "boolean"
+                },
+                "merged_at": {
+                    "type": "string"
+                },
                 "pull_request_id": {
                     "type": "integer"
                 },
                 "pull_request_name": {
                     "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "team_name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "entity.PRDetailResponse": {
+        "entity.PRListResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "pull_requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.PRListItem"
+                    }
+                }
+            }
+        },
+        "entity.PRPreviewResponse": {
             "type": "object",
             "properties": {
                 "author": {
                     "$ref": "#/definitions/entity.UserResponse"
                 },
-                "pull_request_id": {
-                    "type": "integer"
+                "degraded": {
+                    "type": "boolean"
                 },
-                "pull_request_name": {
-                    "type": "string"
+                "explain": {
+                    "$ref": "#/definitions/entity.AssignmentExplanation"
                 },
                 "reviewers": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/entity.UserResponse"
+                        "$ref": "#/definitions/entity.ReviewerResponse"
                     }
-                },
-                "status": {
-                    "type": "string"
                 }
             }
         },
@@ -399,8 +1994,14 @@ const docTemplate = `{
                 "pull_request_name": {
                     "type": "string"
                 },
+                "reviewed_at": {
+                    "type": "string"
+                },
                 "status": {
                     "type": "string"
+                },
+                "verdict": {
+                    "type": "string"
                 }
             }
         },
@@ -431,20 +2032,138 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.ReassignmentResult": {
+            "type": "object",
+            "properties": {
+                "new_reviewer": {
+                    "$ref": "#/definitions/entity.ReviewerResponse"
+                },
+                "old_reviewer_id": {
+                    "type": "integer"
+                },
+                "pull_request_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ReviewerResponse": {
+            "type": "object",
+            "properties": {
+                "assigned_at": {
+                    "type": "string"
+                },
+                "cross_team": {
+                    "type": "boolean"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "team_name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "verdict": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.SubmitReviewRequest": {
+            "type": "object",
+            "required": [
+                "pull_request_id",
+                "reviewer_id",
+                "verdict"
+            ],
+            "properties": {
+                "pull_request_id": {
+                    "type": "integer"
+                },
+                "reviewer_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "verdict": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.SubscriptionCreateRequest": {
+            "type": "object",
+            "required": [
+                "secret",
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.SubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "subscription_id"
+            ],
+            "properties": {
+                "subscription_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "entity.TeamCreateRequest": {
             "type": "object",
             "properties": {
                 "members": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/entity.UserResponse"
+                        "$ref": "#/definitions/entity.TeamMemberRequest"
                     }
                 },
+                "reviewer_strategy": {
+                    "type": "string"
+                },
                 "team_name": {
                     "type": "string"
                 }
             }
         },
+        "entity.TeamMemberRequest": {
+            "type": "object",
+            "properties": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "is_primary": {
+                    "type": "boolean"
+                },
+                "review_weight": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
         "entity.TeamResponse": {
             "type": "object",
             "properties": {
@@ -454,6 +2173,76 @@ const docTemplate = `{
                         "$ref": "#/definitions/entity.UserResponse"
                     }
                 },
+                "reviewer_strategy": {
+                    "type": "string"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.TeamSettingsRequest": {
+            "type": "object",
+            "required": [
+                "team_name"
+            ],
+            "properties": {
+                "fail_on_insufficient": {
+                    "type": "boolean"
+                },
+                "fallback_teams": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_open_reviews": {
+                    "type": "integer"
+                },
+                "min_reviewers": {
+                    "type": "integer"
+                },
+                "required_approvals": {
+                    "type": "integer"
+                },
+                "reviewer_strategy": {
+                    "type": "string"
+                },
+                "reviewers_per_pr": {
+                    "type": "integer"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.TeamSettingsResponse": {
+            "type": "object",
+            "properties": {
+                "fail_on_insufficient": {
+                    "type": "boolean"
+                },
+                "fallback_teams": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_open_reviews": {
+                    "type": "integer"
+                },
+                "min_reviewers": {
+                    "type": "integer"
+                },
+                "required_approvals": {
+                    "type": "integer"
+                },
+                "reviewer_strategy": {
+                    "type": "string"
+                },
+                "reviewers_per_pr": {
+                    "type": "integer"
+                },
                 "team_name": {
                     "type": "string"
                 }
@@ -518,6 +2307,89 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "response_status": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.WebhookResult": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "ignored": {
+                    "description": "Ignored - почему событие не изменило PR (повторная доставка, неподдерживаемое действие)",
+                    "type": "string"
+                },
+                "pull_request_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.WebhookSubscription": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "description": "Events - типы событий; пустой список - все события",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
         "handler.APIError": {
             "type": "object",
             "properties": {
@@ -531,6 +2403,14 @@ const docTemplate = `{
                             "type": "string"
                         }
                     }
+                },
+                "explain": {
+                    "description": "Explain - объяснение выбора ревьюверов, в котором не нашлось нужных кандидатов",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.AssignmentExplanation"
+                        }
+                    ]
                 }
             }
         },
@@ -541,22 +2421,54 @@ const docTemplate = `{
                 "PR_EXISTS",
                 "PR_MERGED",
                 "NOT_ASSIGNED",
+                "ALREADY_ASSIGNED",
                 "NO_CANDIDATE",
-                "NOT_FOUND"
+                "NOT_FOUND",
+                "INVALID_SETTINGS",
+                "NOT_ENOUGH_REVIEWERS",
+                "NOT_ENOUGH_APPROVALS",
+                "INVALID_TRANSITION",
+                "PR_NOT_OPEN",
+                "UNAUTHORIZED",
+                "FORBIDDEN",
+                "INVALID_SIGNATURE",
+                "INVALID_SUBSCRIPTION"
             ],
             "x-enum-varnames": [
                 "ErrCodeTeamExists",
                 "ErrCodePRExists",
                 "ErrCodePRMerged",
                 "ErrCodeNotAssigned",
+                "ErrCodeAlreadyAssigned",
                 "ErrCodeNoCandidate",
-                "ErrCodeNotFound"
+                "ErrCodeNotFound",
+                "ErrCodeInvalidSettings",
+                "ErrCodeNotEnoughReviewers",
+                "ErrCodeNotEnoughApprovals",
+                "ErrCodeInvalidTransition",
+                "ErrCodePRNotOpen",
+                "ErrCodeUnauthorized",
+                "ErrCodeForbidden",
+                "ErrCodeInvalidSignature",
+                "ErrCodeInvalidSubscription"
             ]
+        },
+        "sql.NullTime": {
+            "type": "object",
+            "properties": {
+                "time": {
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Valid is true if Time is not NULL",
+                    "type": "boolean"
+                }
+            }
         }
     },
     "securityDefinitions": {
         "BearerAuth": {
-            "description": "Type \"Bearer\" followed by a space and JWT token.",
+            "description": "Type \"Bearer\" followed by a space and an API token or an SSO JWT.",
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
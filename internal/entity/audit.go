@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Действия журнала аудита
+const (
+	AuditPRCreated          = "pr.created"
+	AuditPRStatusChanged    = "pr.status_changed"
+	AuditReviewerAssigned   = "reviewer.assigned"
+	AuditReviewerUnassigned = "reviewer.unassigned"
+	AuditUserStatusChanged  = "user.status_changed"
+	AuditTeamMemberAdded    = "team.member_added"
+)
+
+// ActorAnonymous - исполнитель действий при выключенной аутентификации
+const ActorAnonymous = "anonymous"
+
+// AuditEntry - запись журнала аудита. Нулевые PullRequestID, UserID и TeamID означают, что объект не затронут.
+type AuditEntry struct {
+	ID            int             `json:"id" db:"id"`
+	Actor         string          `json:"actor" db:"actor"`
+	Action        string          `json:"action" db:"action"`
+	PullRequestID int             `json:"pull_request_id,omitempty" db:"pr_id"`
+	UserID        int             `json:"user_id,omitempty" db:"user_id"`
+	TeamID        int             `json:"team_id,omitempty" db:"team_id"`
+	Before        json.RawMessage `json:"before,omitempty" db:"before_state"`
+	After         json.RawMessage `json:"after,omitempty" db:"after_state"`
+	Reason        string          `json:"reason,omitempty" db:"reason"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditListResponse - ответ /audit, записи от новых к старым.
+type AuditListResponse struct {
+	Entries []AuditEntry `json:"entries"`
+}
+
+// AuditFilter - условия выборки журнала; нулевые поля не ограничивают выборку.
+type AuditFilter struct {
+	PullRequestID int
+	UserID        int
+	TeamID        int
+	From          *time.Time
+	To            *time.Time
+	Limit         int
+}
+
+// AuditRequest - параметры запроса /audit.
+type AuditRequest struct {
+	PullRequestID int        `form:"pull_request_id"`
+	UserID        int        `form:"user_id"`
+	TeamName      string     `form:"team_name"`
+	From          *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To            *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit         int        `form:"limit"`
+}
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	RoleAdmin    = "admin"
+	RoleTeamLead = "team-lead"
+	RoleMember   = "member"
+	RoleBot      = "bot"
+)
+
+type APIToken struct {
+	ID        int        `json:"id" db:"id"`
+	Name      string     `json:"name" db:"name"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	Role      string     `json:"role" db:"role"`
+	UserID    *int       `json:"user_id,omitempty" db:"user_id"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// IssuedToken - выпущенный токен вместе с открытым значением, которое больше нигде не хранится.
+type IssuedToken struct {
+	APIToken
+	Token string `json:"token"`
+}
+
+// Principal - аутентифицированный вызывающий.
+type Principal struct {
+	TokenID  int    `json:"token_id"`
+	Role     string `json:"role"`
+	UserID   int    `json:"user_id,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// Actor - имя вызывающего для журнала аудита: пользователь или, для токена бота, номер токена.
+func (p *Principal) Actor() string {
+	if p.Username != "" {
+		return p.Username
+	}
+	return fmt.Sprintf("token:%d", p.TokenID)
+}
+
+// HasRole сообщает, входит ли роль вызывающего в roles.
+func (p *Principal) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		if p.Role == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package entity
+
+// Причины, по которым участник команды не попал в кандидаты
+const (
+	ExcludedAuthor          = "author"
+	ExcludedInactive        = "inactive"
+	ExcludedAlreadyAssigned = "already_assigned"
+	ExcludedAlreadySelected = "already_selected"
+	ExcludedOverCapacity    = "over_capacity"
+)
+
+// AssignmentExplanation объясняет выбор ревьюверов: настройки команды PR
+// и пулы кандидатов по порядку, в котором они просматривались.
+// Seed - зерно случайности выбора, по нему выбор воспроизводится при тех же кандидатах.
+type AssignmentExplanation struct {
+	Seed           int64           `json:"seed"`
+	Strategy       string          `json:"strategy"`
+	ReviewersPerPR int             `json:"reviewers_per_pr"`
+	MinReviewers   int             `json:"min_reviewers"`
+	Pools          []CandidatePool `json:"pools"`
+	Degraded       bool            `json:"degraded"`
+}
+
+// CandidatePool - кандидаты одного шага выбора: команды автора (или ревьювера при замене) либо резервная команда.
+type CandidatePool struct {
+	Teams      []string            `json:"teams"`
+	Fallback   bool                `json:"fallback"`
+	Strategy   string              `json:"strategy"`
+	Needed     int                 `json:"needed"`
+	Candidates []int               `json:"candidates"`
+	Excluded   []ExcludedCandidate `json:"excluded"`
+	Selected   []int               `json:"selected"`
+}
+
+type ExcludedCandidate struct {
+	UserID   int    `json:"user_id"`
+	Username string `json:"username"`
+	Reason   string `json:"reason"`
+}
+
+// PRPreviewResponse - кого назначил бы сервис, если бы PR был создан сейчас.
+type PRPreviewResponse struct {
+	Author    UserResponse           `json:"author"`
+	Reviewers []ReviewerResponse     `json:"reviewers"`
+	Degraded  bool                   `json:"degraded"`
+	Explain   *AssignmentExplanation `json:"explain"`
+}
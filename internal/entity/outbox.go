@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEvent - доменное событие, записанное в outbox вместе с изменением, которое его вызвало.
+type OutboxEvent struct {
+	ID        int             `json:"id" db:"id"`
+	EventID   string          `json:"event_id" db:"event_id"`
+	EventType string          `json:"event_type" db:"event_type"`
+	Payload   json.RawMessage `json:"payload" db:"payload"`
+	Attempts  int             `json:"attempts" db:"attempts"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,74 @@
+package entity
+
+import "time"
+
+// Поля сортировки списка PR
+const (
+	PRSortCreatedAt = "created_at"
+	PRSortUpdatedAt = "updated_at"
+	PRSortTitle     = "title"
+	PRSortID        = "id"
+)
+
+// PRListRequest - параметры запроса /pullRequest/list.
+type PRListRequest struct {
+	Status      string     `form:"status"`
+	AuthorID    int        `form:"author_id"`
+	ReviewerID  int        `form:"reviewer_id"`
+	TeamName    string     `form:"team_name"`
+	Title       string     `form:"title"`
+	CreatedFrom *time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   *time.Time `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	MergedFrom  *time.Time `form:"merged_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	MergedTo    *time.Time `form:"merged_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Sort        string     `form:"sort"`
+	Order       string     `form:"order"`
+	Limit       int        `form:"limit"`
+	Cursor      string     `form:"cursor"`
+}
+
+// PRListFilter - выборка PR для PRRepository.List. Нулевые значения не ограничивают выборку.
+// After - последний PR предыдущей страницы: выдаются PR, следующие за ним в порядке сортировки.
+type PRListFilter struct {
+	Status      string
+	AuthorID    int
+	ReviewerID  int
+	TeamID      int
+	Title       string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MergedFrom  *time.Time
+	MergedTo    *time.Time
+	Sort        string
+	Desc        bool
+	After       *PRListCursor
+	Limit       int
+}
+
+// PRListCursor - позиция в списке PR: значение поля сортировки и id последнего PR страницы.
+// Значение берется из курсора, а не из PR, поэтому изменение PR не сдвигает следующую страницу.
+// Time заполняется для сортировки по created_at и updated_at, Title - по title.
+type PRListCursor struct {
+	ID    int
+	Time  time.Time
+	Title string
+}
+
+type PRListItem struct {
+	PullRequestID   int        `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+	AuthorID        int        `json:"author_id"`
+	TeamName        string     `json:"team_name,omitempty"`
+	Status          string     `json:"status"`
+	Degraded        bool       `json:"degraded"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	MergedAt        *time.Time `json:"merged_at,omitempty"`
+	ClosedAt        *time.Time `json:"closed_at,omitempty"`
+}
+
+// PRListResponse - страница списка PR. NextCursor пуст на последней странице.
+type PRListResponse struct {
+	PullRequests []PRListItem `json:"pull_requests"`
+	NextCursor   string       `json:"next_cursor,omitempty"`
+}
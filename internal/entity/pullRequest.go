@@ -5,14 +5,30 @@ import (
 	"time"
 )
 
+const (
+	StatusDraft  = "DRAFT"
+	StatusOpen   = "OPEN"
+	StatusMerged = "MERGED"
+	StatusClosed = "CLOSED"
+)
+
+const (
+	VerdictApproved         = "APPROVED"
+	VerdictChangesRequested = "CHANGES_REQUESTED"
+	VerdictCommented        = "COMMENTED"
+)
+
 type PullRequest struct {
 	ID        int          `json:"id" db:"id"`
 	Title     string       `json:"title" db:"title" binding:"required"`
 	AuthorID  int          `json:"author_id" db:"author_id" binding:"required"`
+	TeamID    *int         `json:"team_id" db:"team_id"`
 	Status    string       `json:"status" db:"status"`
 	CreatedAt time.Time    `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"`
 	MergedAt  sql.NullTime `json:"merged_at" db:"merged_at"`
+	ClosedAt  sql.NullTime `json:"closed_at" db:"closed_at"`
+	Degraded  bool         `json:"degraded" db:"degraded"`
 }
 
 type UpdatePRStatusRequest struct {
@@ -24,30 +40,61 @@ type ReassignReviewerRequest struct {
 	OldReviewerID int `json:"old_reviewer_id" binding:"required,min=1"`
 }
 
+type SubmitReviewRequest struct {
+	PullRequestID int    `json:"pull_request_id" binding:"required"`
+	ReviewerID    int    `json:"reviewer_id" binding:"required,min=1"`
+	Verdict       string `json:"verdict" binding:"required"`
+}
+
 type PRCreateRequest struct {
 	PullRequestID   int    `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
 	AuthorID        int    `json:"author_id"`
+	TeamName        string `json:"team_name,omitempty"`
+	Draft           bool   `json:"draft,omitempty"`
 }
 
 type PRDetailResponse struct {
-	PullRequestID   int            `json:"pull_request_id"`
-	PullRequestName string         `json:"pull_request_name"`
-	Author          UserResponse   `json:"author"`
-	Status          string         `json:"status"`
-	Reviewers       []UserResponse `json:"reviewers"`
+	PullRequestID   int                `json:"pull_request_id"`
+	PullRequestName string             `json:"pull_request_name"`
+	Author          UserResponse       `json:"author"`
+	Status          string             `json:"status"`
+	Reviewers       []ReviewerResponse `json:"reviewers"`
+	Degraded        bool               `json:"degraded"`
+	MergedAt        *time.Time         `json:"merged_at,omitempty"`
+	// Explain - как выбраны ревьюверы; заполняется операциями, которые назначают ревьюверов
+	Explain *AssignmentExplanation `json:"explain,omitempty"`
 }
 
 type MergedPRResponse struct {
-	PullRequestID   int            `json:"pull_request_id"`
-	PullRequestName string         `json:"pull_request_name"`
-	Author          UserResponse   `json:"author"`
-	Status          string         `json:"status"`
-	Reviewers       []UserResponse `json:"reviewers"`
-	MergedAt        sql.NullTime   `json:"merged_at" db:"merged_at"`
+	PullRequestID   int                `json:"pull_request_id"`
+	PullRequestName string             `json:"pull_request_name"`
+	Author          UserResponse       `json:"author"`
+	Status          string             `json:"status"`
+	Reviewers       []ReviewerResponse `json:"reviewers"`
+	MergedAt        sql.NullTime       `json:"merged_at" db:"merged_at"`
 }
 
 type ReassignHandlerResponse struct {
 	Pr         PRDetailResponse `json:"pr"`
 	ReplacedBy string           `json:"replaced_by"`
 }
+
+type ReviewerResponse struct {
+	UserID     int        `json:"user_id"`
+	Username   string     `json:"username"`
+	TeamID     int        `json:"-"`
+	TeamName   string     `json:"team_name"`
+	IsActive   bool       `json:"is_active"`
+	CrossTeam  bool       `json:"cross_team"`
+	Verdict    string     `json:"verdict,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	AssignedAt *time.Time `json:"assigned_at,omitempty"`
+	// Reason - почему выбран ревьювер; заполняется при выборе и пишется в журнал аудита
+	Reason string `json:"-"`
+}
+
+type ReviewAssignment struct {
+	PullRequestID int `json:"pull_request_id" db:"pr_id"`
+	ReviewerID    int `json:"reviewer_id" db:"reviewer_id"`
+}
@@ -0,0 +1,96 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Типы доменных событий; на них же подписываются исходящие вебхуки
+const (
+	EventPRCreated         = "pr.created"
+	EventReviewerAssigned  = "reviewer.assigned"
+	EventReviewerReplaced  = "reviewer.replaced"
+	EventPRMerged          = "pr.merged"
+	EventPRClosed          = "pr.closed"
+	EventPRReady           = "pr.ready"
+	EventPRReopened        = "pr.reopened"
+	EventUserStatusChanged = "user.status_changed"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+type WebhookSubscription struct {
+	ID     int    `json:"id" db:"id"`
+	URL    string `json:"url" db:"url"`
+	Secret string `json:"-" db:"secret"`
+	// Events - типы событий; пустой список - все события
+	Events    []string  `json:"events" db:"events"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type SubscriptionCreateRequest struct {
+	URL    string   `json:"url" binding:"required"`
+	Secret string   `json:"secret" binding:"required"`
+	Events []string `json:"events"`
+}
+
+type SubscriptionRequest struct {
+	SubscriptionID int `json:"subscription_id" binding:"required"`
+}
+
+type WebhookDelivery struct {
+	ID             int             `json:"id" db:"id"`
+	SubscriptionID int             `json:"subscription_id" db:"subscription_id"`
+	EventID        string          `json:"event_id" db:"event_id"`
+	EventType      string          `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	ResponseStatus *int            `json:"response_status,omitempty" db:"response_status"`
+	LastError      *string         `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty" db:"delivered_at"`
+}
+
+type DeliveryFilter struct {
+	SubscriptionID int
+	Status         string
+	Limit          int
+}
+
+type DeliveryRequest struct {
+	DeliveryID int `json:"delivery_id" binding:"required"`
+}
+
+// DeliveryAttempt - итог одной попытки доставки.
+// Неуспешная попытка с RetryIn > 0 повторяется через RetryIn, без него доставка считается проваленной.
+type DeliveryAttempt struct {
+	Succeeded      bool
+	ResponseStatus int
+	Error          string
+	RetryIn        time.Duration
+}
+
+// Event - конверт доменного события: так оно хранится в outbox и так его получает подписчик.
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+type ReviewerAssignedEvent struct {
+	PullRequestID int              `json:"pull_request_id"`
+	Reviewer      ReviewerResponse `json:"reviewer"`
+}
+
+type ReviewerReplacedEvent struct {
+	PullRequestID int              `json:"pull_request_id"`
+	OldReviewerID int              `json:"old_reviewer_id"`
+	NewReviewer   ReviewerResponse `json:"new_reviewer"`
+}
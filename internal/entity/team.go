@@ -2,6 +2,13 @@ package entity
 
 import "time"
 
+const (
+	StrategyRandom      = "random"
+	StrategyRoundRobin  = "round_robin"
+	StrategyLeastLoaded = "least_loaded"
+	StrategyWeighted    = "weighted"
+)
+
 type Team struct {
 	ID        int       `json:"id" db:"id"`
 	Name      string    `json:"name" db:"name" binding:"required"`
@@ -9,12 +16,56 @@ type Team struct {
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
+const DefaultReviewersPerPR = 2
+
+type TeamSettings struct {
+	TeamID             int       `json:"team_id" db:"team_id"`
+	ReviewerStrategy   string    `json:"reviewer_strategy" db:"reviewer_strategy"`
+	ReviewersPerPR     int       `json:"reviewers_per_pr" db:"reviewers_per_pr"`
+	MinReviewers       int       `json:"min_reviewers" db:"min_reviewers"`
+	FailOnInsufficient bool      `json:"fail_on_insufficient" db:"fail_on_insufficient"`
+	RequiredApprovals  int       `json:"required_approvals" db:"required_approvals"`
+	MaxOpenReviews     int       `json:"max_open_reviews" db:"max_open_reviews"`
+	UpdatedAt          time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type TeamSettingsRequest struct {
+	TeamName           string    `json:"team_name" binding:"required"`
+	ReviewerStrategy   *string   `json:"reviewer_strategy,omitempty"`
+	ReviewersPerPR     *int      `json:"reviewers_per_pr,omitempty"`
+	MinReviewers       *int      `json:"min_reviewers,omitempty"`
+	FailOnInsufficient *bool     `json:"fail_on_insufficient,omitempty"`
+	RequiredApprovals  *int      `json:"required_approvals,omitempty"`
+	MaxOpenReviews     *int      `json:"max_open_reviews,omitempty"`
+	FallbackTeams      *[]string `json:"fallback_teams,omitempty"`
+}
+
+type TeamSettingsResponse struct {
+	TeamName           string   `json:"team_name"`
+	ReviewerStrategy   string   `json:"reviewer_strategy"`
+	ReviewersPerPR     int      `json:"reviewers_per_pr"`
+	MinReviewers       int      `json:"min_reviewers"`
+	FailOnInsufficient bool     `json:"fail_on_insufficient"`
+	RequiredApprovals  int      `json:"required_approvals"`
+	MaxOpenReviews     int      `json:"max_open_reviews"`
+	FallbackTeams      []string `json:"fallback_teams"`
+}
+
+type TeamMemberRequest struct {
+	Username     string `json:"username"`
+	IsActive     bool   `json:"is_active"`
+	ReviewWeight int    `json:"review_weight,omitempty"`
+	IsPrimary    bool   `json:"is_primary,omitempty"`
+}
+
 type TeamCreateRequest struct {
-	TeamName string         `json:"team_name"`
-	Members  []UserResponse `json:"members"`
+	TeamName         string              `json:"team_name"`
+	ReviewerStrategy string              `json:"reviewer_strategy,omitempty"`
+	Members          []TeamMemberRequest `json:"members"`
 }
 
 type TeamResponse struct {
-	TeamName string         `json:"team_name"`
-	Members  []UserResponse `json:"members"`
+	TeamName         string         `json:"team_name"`
+	ReviewerStrategy string         `json:"reviewer_strategy"`
+	Members          []UserResponse `json:"members"`
 }
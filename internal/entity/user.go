@@ -29,8 +29,28 @@ type UserReviewsResponse struct {
 }
 
 type PRSummary struct {
-	PullRequestID   int    `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        int    `json:"author_id"`
-	Status          string `json:"status"`
+	PullRequestID   int        `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+	AuthorID        int        `json:"author_id"`
+	Status          string     `json:"status"`
+	Verdict         string     `json:"verdict,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+}
+
+type BulkDeactivateRequest struct {
+	UserIDs  []int  `json:"user_ids"`
+	TeamName string `json:"team_name,omitempty"`
+}
+
+type ReassignmentResult struct {
+	PullRequestID int               `json:"pull_request_id"`
+	OldReviewerID int               `json:"old_reviewer_id"`
+	NewReviewer   *ReviewerResponse `json:"new_reviewer,omitempty"`
+	Reason        string            `json:"reason,omitempty"`
+}
+
+type BulkDeactivateResponse struct {
+	Deactivated []UserResponse       `json:"deactivated"`
+	Reassigned  []ReassignmentResult `json:"reassigned"`
+	Unfilled    []ReassignmentResult `json:"unfilled"`
 }
@@ -0,0 +1,76 @@
+package entity
+
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// Действия с PR во внешней системе, к которым сводятся события разных провайдеров
+const (
+	ExternalActionOpened   = "opened"
+	ExternalActionReady    = "ready"
+	ExternalActionMerged   = "merged"
+	ExternalActionClosed   = "closed"
+	ExternalActionReopened = "reopened"
+)
+
+// ExternalPREvent - событие PR из внешней системы, не зависящее от провайдера.
+type ExternalPREvent struct {
+	Provider    string
+	Repository  string
+	Number      int
+	Action      string
+	Title       string
+	AuthorLogin string
+	Draft       bool
+}
+
+type WebhookResult struct {
+	Action        string `json:"action"`
+	PullRequestID int    `json:"pull_request_id,omitempty"`
+	Status        string `json:"status,omitempty"`
+	// Ignored - почему событие не изменило PR (повторная доставка, неподдерживаемое действие)
+	Ignored string `json:"ignored,omitempty"`
+}
+
+// GitHubPullRequestEvent - нужные сервису поля события pull_request GitHub.
+type GitHubPullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Title  string `json:"title"`
+		Draft  bool   `json:"draft"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// GitLabMergeRequestEvent - нужные сервису поля события Merge Request Hook GitLab.
+// Логина автора в событии нет, только его id, поэтому автором открытого MR считается user - тот, кто его открыл.
+type GitLabMergeRequestEvent struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID            int    `json:"iid"`
+		Title          string `json:"title"`
+		Action         string `json:"action"`
+		Draft          bool   `json:"draft"`
+		WorkInProgress bool   `json:"work_in_progress"`
+	} `json:"object_attributes"`
+	Changes struct {
+		Draft *struct {
+			Previous bool `json:"previous"`
+			Current  bool `json:"current"`
+		} `json:"draft"`
+	} `json:"changes"`
+}
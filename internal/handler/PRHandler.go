@@ -2,12 +2,15 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgxpool"
 
 	"PR-appointer/internal/entity"
+	"PR-appointer/internal/middleware"
+	"PR-appointer/internal/repository"
 	"PR-appointer/internal/service"
 )
 
@@ -15,22 +18,26 @@ type PRHandler struct {
 	prService *service.PRService
 }
 
-func NewPRHandler(ctx context.Context, db *pgxpool.Pool) *PRHandler {
+func NewPRHandler(ctx context.Context, store *repository.Store, seeds *service.SeedSource) *PRHandler {
 	return &PRHandler{
-		prService: service.NewPRService(db),
+		prService: service.NewPRService(store, seeds),
 	}
 }
 
 // CreatePR godoc
 // @Summary Create PR with auto-assigned reviewers
-// @Description Create PR and automatically assign up to 2 reviewers from author's team
+// @Description Create PR and automatically assign reviewers from author's team according to team settings. Draft PRs get reviewers when marked ready
 // @Tags PullRequests
 // @Accept json
 // @Produce json
 // @Param request body entity.PRCreateRequest true "PR data"
+// @Param explain query bool false "Include reviewer selection explanation"
 // @Success 201 {object} entity.PRDetailResponse
 // @Failure 404 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 403 {object} APIError
 // @Failure 409 {object} APIError
+// @Security BearerAuth
 // @Router /pullRequest/create [post]
 func (h *PRHandler) CreatePR(c *gin.Context) {
 	var req entity.PRCreateRequest
@@ -40,6 +47,11 @@ func (h *PRHandler) CreatePR(c *gin.Context) {
 		return
 	}
 
+	// Участники создают PR только от своего имени
+	if !authorize(c, []int{req.AuthorID}, entity.RoleAdmin, entity.RoleBot) {
+		return
+	}
+
 	pr, err := h.prService.CreatePR(c.Request.Context(), &req)
 	if err != nil {
 		// Check specific error types
@@ -51,22 +63,78 @@ func (h *PRHandler) CreatePR(c *gin.Context) {
 			c.JSON(http.StatusNotFound, newAPIError(ErrCodeNotFound, err.Error()))
 			return
 		}
+		if err.Error() == "not enough active reviewers in team" {
+			c.JSON(http.StatusConflict, withSelectionExplain(newAPIError(ErrCodeNotEnoughReviewers, err.Error()), err))
+			return
+		}
+		if err.Error() == "reviewer is already assigned to this PR" {
+			c.JSON(http.StatusConflict, newAPIError(ErrCodeAlreadyAssigned, err.Error()))
+			return
+		}
 		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"pr": pr})
+	c.JSON(http.StatusCreated, gin.H{"pr": withExplain(c, pr)})
+}
+
+// PreviewPR godoc
+// @Summary Preview reviewer assignment
+// @Description Run reviewer selection for a would-be PR without persisting anything and explain the choice
+// @Tags PullRequests
+// @Accept json
+// @Produce json
+// @Param request body entity.PRCreateRequest true "PR data"
+// @Success 200 {object} entity.PRPreviewResponse
+// @Failure 404 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 409 {object} APIError
+// @Security BearerAuth
+// @Router /pullRequest/preview [post]
+func (h *PRHandler) PreviewPR(c *gin.Context) {
+	var req entity.PRCreateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
+		return
+	}
+
+	if !authorize(c, []int{req.AuthorID}, entity.RoleAdmin, entity.RoleTeamLead, entity.RoleBot) {
+		return
+	}
+
+	preview, err := h.prService.PreviewPR(c.Request.Context(), &req)
+	if err != nil {
+		switch err.Error() {
+		case "author not found", "team not found":
+			c.JSON(http.StatusNotFound, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		case "not enough active reviewers in team":
+			c.JSON(http.StatusConflict, withSelectionExplain(newAPIError(ErrCodeNotEnoughReviewers, err.Error()), err))
+			return
+		default:
+			c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"preview": preview})
 }
 
 // MergePR godoc
 // @Summary Mark PR as merged
-// @Description Set PR status to MERGED (idempotent operation)
+// @Description Set PR status to MERGED (idempotent operation). Blocked until the team's required approvals are reached
 // @Tags PullRequests
 // @Accept json
 // @Produce json
 // @Param request body entity.UpdatePRStatusRequest true "PR ID"
 // @Success 200 {object} entity.MergedPRResponse
 // @Failure 404 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 409 {object} APIError
+// @Security BearerAuth
 // @Router /pullRequest/merge [post]
 func (h *PRHandler) MergePR(c *gin.Context) {
 	var req entity.UpdatePRStatusRequest
@@ -76,25 +144,186 @@ func (h *PRHandler) MergePR(c *gin.Context) {
 		return
 	}
 
+	if !h.authorizePR(c, req.PullRequestID, entity.RoleBot) {
+		return
+	}
+
 	pr, err := h.prService.MergePR(c.Request.Context(), req.PullRequestID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, newAPIError(ErrCodeNotFound, "PR not found"))
+		switch err.Error() {
+		case "not enough approvals to merge":
+			c.JSON(http.StatusConflict, newAPIError(ErrCodeNotEnoughApprovals, err.Error()))
+			return
+		case "invalid PR status transition":
+			c.JSON(http.StatusConflict, newAPIError(ErrCodeInvalidTransition, err.Error()))
+			return
+		default:
+			c.JSON(http.StatusNotFound, newAPIError(ErrCodeNotFound, "PR not found"))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": pr})
+}
+
+// MarkReady godoc
+// @Summary Mark draft PR as ready for review
+// @Description Move PR from DRAFT to OPEN and assign reviewers
+// @Tags PullRequests
+// @Accept json
+// @Produce json
+// @Param request body entity.UpdatePRStatusRequest true "PR ID"
+// @Param explain query bool false "Include reviewer selection explanation"
+// @Success 200 {object} entity.PRDetailResponse
+// @Failure 404 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 409 {object} APIError
+// @Security BearerAuth
+// @Router /pullRequest/ready [post]
+func (h *PRHandler) MarkReady(c *gin.Context) {
+	h.changeStatus(c, h.prService.MarkReady)
+}
+
+// ClosePR godoc
+// @Summary Close PR without merge
+// @Description Move PR from DRAFT or OPEN to CLOSED and release its reviewers
+// @Tags PullRequests
+// @Accept json
+// @Produce json
+// @Param request body entity.UpdatePRStatusRequest true "PR ID"
+// @Success 200 {object} entity.PRDetailResponse
+// @Failure 404 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 409 {object} APIError
+// @Security BearerAuth
+// @Router /pullRequest/close [post]
+func (h *PRHandler) ClosePR(c *gin.Context) {
+	h.changeStatus(c, h.prService.ClosePR)
+}
+
+// ReopenPR godoc
+// @Summary Reopen closed PR
+// @Description Move PR from CLOSED to OPEN and assign reviewers again
+// @Tags PullRequests
+// @Accept json
+// @Produce json
+// @Param request body entity.UpdatePRStatusRequest true "PR ID"
+// @Param explain query bool false "Include reviewer selection explanation"
+// @Success 200 {object} entity.PRDetailResponse
+// @Failure 404 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 409 {object} APIError
+// @Security BearerAuth
+// @Router /pullRequest/reopen [post]
+func (h *PRHandler) ReopenPR(c *gin.Context) {
+	h.changeStatus(c, h.prService.ReopenPR)
+}
+
+func (h *PRHandler) changeStatus(c *gin.Context, transition func(ctx context.Context, prID int) (*entity.PRDetailResponse, error)) {
+	var req entity.UpdatePRStatusRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
+		return
+	}
+
+	if !h.authorizePR(c, req.PullRequestID, entity.RoleAdmin, entity.RoleBot) {
+		return
+	}
+
+	pr, err := transition(c.Request.Context(), req.PullRequestID)
+	if err != nil {
+		switch err.Error() {
+		case "PR not found":
+			c.JSON(http.StatusNotFound, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		case "invalid PR status transition":
+			c.JSON(http.StatusConflict, newAPIError(ErrCodeInvalidTransition, err.Error()))
+			return
+		case "not enough active reviewers in team":
+			c.JSON(http.StatusConflict, withSelectionExplain(newAPIError(ErrCodeNotEnoughReviewers, err.Error()), err))
+			return
+		case "reviewer is already assigned to this PR":
+			c.JSON(http.StatusConflict, newAPIError(ErrCodeAlreadyAssigned, err.Error()))
+			return
+		default:
+			c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": withExplain(c, pr)})
+}
+
+// SubmitReview godoc
+// @Summary Submit review verdict
+// @Description Reviewer submits a verdict (APPROVED, CHANGES_REQUESTED, COMMENTED) for the PR
+// @Tags PullRequests
+// @Accept json
+// @Produce json
+// @Param request body entity.SubmitReviewRequest true "Review data"
+// @Success 200 {object} entity.PRDetailResponse
+// @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 409 {object} APIError
+// @Security BearerAuth
+// @Router /pullRequest/review [post]
+func (h *PRHandler) SubmitReview(c *gin.Context) {
+	var req entity.SubmitReviewRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
 		return
 	}
 
+	// Вердикт ставит сам ревьювер или бот от его имени
+	if !authorize(c, []int{req.ReviewerID}, entity.RoleBot) {
+		return
+	}
+
+	pr, err := h.prService.SubmitReview(c.Request.Context(), &req)
+	if err != nil {
+		switch err.Error() {
+		case "PR not found":
+			c.JSON(http.StatusNotFound, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		case "cannot review merged PR":
+			c.JSON(http.StatusConflict, newAPIError(ErrCodePRMerged, err.Error()))
+			return
+		case "reviewer is not assigned to this PR":
+			c.JSON(http.StatusConflict, newAPIError(ErrCodeNotAssigned, err.Error()))
+			return
+		case "PR is not open":
+			c.JSON(http.StatusConflict, newAPIError(ErrCodePRNotOpen, err.Error()))
+			return
+		default:
+			c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"pr": pr})
 }
 
 // ReassignReviewer godoc
 // @Summary Reassign reviewer
-// @Description Replace one reviewer with another from same team
+// @Description Replace one reviewer with another from same team (or from its fallback teams)
 // @Tags PullRequests
 // @Accept json
 // @Produce json
 // @Param request body entity.ReassignReviewerRequest true "Reassignment data"
+// @Param explain query bool false "Include reviewer selection explanation"
 // @Success 200 {object} entity.ReassignHandlerResponse
 // @Failure 404 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 403 {object} APIError
 // @Failure 409 {object} APIError
+// @Security BearerAuth
 // @Router /pullRequest/reassign [post]
 func (h *PRHandler) ReassignReviewer(c *gin.Context) {
 	var req entity.ReassignReviewerRequest
@@ -104,6 +333,10 @@ func (h *PRHandler) ReassignReviewer(c *gin.Context) {
 		return
 	}
 
+	if !h.authorizePR(c, req.PullRequestID, entity.RoleAdmin, entity.RoleTeamLead, entity.RoleBot) {
+		return
+	}
+
 	pr, newReviewerID, err := h.prService.ReassignReviewer(c.Request.Context(), req.PullRequestID, req.OldReviewerID)
 	if err != nil {
 		// Check specific error types
@@ -117,8 +350,14 @@ func (h *PRHandler) ReassignReviewer(c *gin.Context) {
 		case "reviewer is not assigned to this PR":
 			c.JSON(http.StatusConflict, newAPIError(ErrCodeNotAssigned, err.Error()))
 			return
+		case "PR is not open":
+			c.JSON(http.StatusConflict, newAPIError(ErrCodePRNotOpen, err.Error()))
+			return
 		case "no active replacement candidate in team":
-			c.JSON(http.StatusConflict, newAPIError(ErrCodeNoCandidate, err.Error()))
+			c.JSON(http.StatusConflict, withSelectionExplain(newAPIError(ErrCodeNoCandidate, err.Error()), err))
+			return
+		case "reviewer is already assigned to this PR":
+			c.JSON(http.StatusConflict, newAPIError(ErrCodeAlreadyAssigned, err.Error()))
 			return
 		default:
 			c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
@@ -127,7 +366,126 @@ func (h *PRHandler) ReassignReviewer(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"pr":          pr,
+		"pr":          withExplain(c, pr),
 		"replaced_by": newReviewerID,
 	})
 }
+
+// GetPR godoc
+// @Summary Get PR with reviewers
+// @Description Get PR by ID with its reviewers, the time each reviewer was assigned and the merge time
+// @Tags PullRequests
+// @Produce json
+// @Param pull_request_id query int true "PR ID"
+// @Success 200 {object} entity.PRDetailResponse
+// @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 401 {object} APIError
+// @Security BearerAuth
+// @Router /pullRequest/get [get]
+func (h *PRHandler) GetPR(c *gin.Context) {
+	prID, err := strconv.Atoi(c.Query("pull_request_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, "pull_request_id is required"))
+		return
+	}
+
+	pr, err := h.prService.GetPRDetails(c.Request.Context(), prID)
+	if err != nil {
+		if err.Error() == "PR not found" {
+			c.JSON(http.StatusNotFound, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, newAPIError(ErrCodeNotFound, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": pr})
+}
+
+// ListPRs godoc
+// @Summary List pull requests
+// @Description List PRs with filters, newest first by default. Pages are fetched with next_cursor from the previous response
+// @Tags PullRequests
+// @Produce json
+// @Param status query string false "PR status: DRAFT, OPEN, MERGED or CLOSED"
+// @Param author_id query int false "Author ID"
+// @Param reviewer_id query int false "ID of a currently assigned reviewer"
+// @Param team_name query string false "PR team name"
+// @Param title query string false "Case-insensitive title substring"
+// @Param created_from query string false "Created at or after, RFC 3339"
+// @Param created_to query string false "Created before, RFC 3339"
+// @Param merged_from query string false "Merged at or after, RFC 3339"
+// @Param merged_to query string false "Merged before, RFC 3339"
+// @Param sort query string false "Sort field: created_at (default), updated_at, title or id"
+// @Param order query string false "Sort order: desc (default) or asc"
+// @Param limit query int false "Page size, 50 by default, up to 200"
+// @Param cursor query string false "next_cursor from the previous page, valid only with the same sort and order"
+// @Success 200 {object} entity.PRListResponse
+// @Failure 400 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 404 {object} APIError
+// @Security BearerAuth
+// @Router /pullRequest/list [get]
+func (h *PRHandler) ListPRs(c *gin.Context) {
+	var req entity.PRListRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
+		return
+	}
+
+	prs, err := h.prService.ListPRs(c.Request.Context(), &req)
+	if err != nil {
+		switch err.Error() {
+		case "team not found":
+			c.JSON(http.StatusNotFound, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		case "unknown PR status", "unknown sort field", "order must be asc or desc", "from must be before to", "invalid cursor",
+			"cursor does not match sort and order":
+			c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, prs)
+}
+
+// authorizePR пропускает автора PR и вызывающих с ролями roles.
+// Несуществующий PR не проверяется, чтобы сервис ответил обычным 404; другие ошибки дают 500.
+func (h *PRHandler) authorizePR(c *gin.Context, prID int, roles ...string) bool {
+	if middleware.PrincipalFrom(c) == nil {
+		return true
+	}
+
+	pr, err := h.prService.GetPR(c.Request.Context(), prID)
+	if err != nil {
+		if err.Error() == "PR not found" {
+			return true
+		}
+		c.JSON(http.StatusInternalServerError, newAPIError(ErrCodeNotFound, err.Error()))
+		return false
+	}
+
+	return authorize(c, []int{pr.AuthorID}, roles...)
+}
+
+// withExplain оставляет объяснение выбора ревьюверов только по запросу ?explain=true.
+func withExplain(c *gin.Context, pr *entity.PRDetailResponse) *entity.PRDetailResponse {
+	if explain, _ := strconv.ParseBool(c.Query("explain")); !explain {
+		pr.Explain = nil
+	}
+	return pr
+}
+
+// withSelectionExplain добавляет к отказу в выборе ревьюверов объяснение, почему кандидатов не хватило.
+func withSelectionExplain(apiErr APIError, err error) APIError {
+	var selectionErr *service.SelectionError
+	if errors.As(err, &selectionErr) {
+		apiErr.Explain = selectionErr.Explain
+	}
+	return apiErr
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+
+	"PR-appointer/internal/middleware"
+)
+
+// authorize пропускает вызывающего, если он один из владельцев ownerIDs или имеет одну из ролей roles.
+// Иначе отвечает 403 и возвращает false. Без аутентификации проверка не выполняется.
+func authorize(c *gin.Context, ownerIDs []int, roles ...string) bool {
+	principal := middleware.PrincipalFrom(c)
+	if principal == nil || principal.HasRole(roles...) {
+		return true
+	}
+	if principal.UserID != 0 && slices.Contains(ownerIDs, principal.UserID) {
+		return true
+	}
+
+	c.JSON(http.StatusForbidden, newAPIError(ErrCodeForbidden, "insufficient permissions"))
+	return false
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+	"PR-appointer/internal/service"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+func NewAuditHandler(ctx context.Context, store *repository.Store) *AuditHandler {
+	return &AuditHandler{
+		auditService: service.NewAuditService(store),
+	}
+}
+
+// GetAudit godoc
+// @Summary Get audit log
+// @Description Get assignment, reassignment, PR status, user status and team membership changes, newest first. Each entry has the actor, before and after state and, for assignments, the reason the reviewer was picked or removed
+// @Tags Audit
+// @Produce json
+// @Param pull_request_id query int false "PR ID"
+// @Param user_id query int false "User ID"
+// @Param team_name query string false "Team name"
+// @Param from query string false "Start of the time range, RFC 3339, inclusive"
+// @Param to query string false "End of the time range, RFC 3339, exclusive"
+// @Param limit query int false "Maximum number of entries, 100 by default, up to 500"
+// @Success 200 {object} entity.AuditListResponse
+// @Failure 400 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 404 {object} APIError
+// @Security BearerAuth
+// @Router /audit [get]
+func (h *AuditHandler) GetAudit(c *gin.Context) {
+	var req entity.AuditRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
+		return
+	}
+
+	entries, err := h.auditService.List(c.Request.Context(), &req)
+	if err != nil {
+		switch err.Error() {
+		case "team not found":
+			c.JSON(http.StatusNotFound, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		case "from must be before to":
+			c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, entity.AuditListResponse{Entries: entries})
+}
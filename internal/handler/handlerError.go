@@ -1,14 +1,26 @@
 package handler
 
+import "PR-appointer/internal/entity"
+
 type ErrorCode string
 
 const (
-	ErrCodeTeamExists  ErrorCode = "TEAM_EXISTS"
-	ErrCodePRExists    ErrorCode = "PR_EXISTS"
-	ErrCodePRMerged    ErrorCode = "PR_MERGED"
-	ErrCodeNotAssigned ErrorCode = "NOT_ASSIGNED"
-	ErrCodeNoCandidate ErrorCode = "NO_CANDIDATE"
-	ErrCodeNotFound    ErrorCode = "NOT_FOUND"
+	ErrCodeTeamExists          ErrorCode = "TEAM_EXISTS"
+	ErrCodePRExists            ErrorCode = "PR_EXISTS"
+	ErrCodePRMerged            ErrorCode = "PR_MERGED"
+	ErrCodeNotAssigned         ErrorCode = "NOT_ASSIGNED"
+	ErrCodeAlreadyAssigned     ErrorCode = "ALREADY_ASSIGNED"
+	ErrCodeNoCandidate         ErrorCode = "NO_CANDIDATE"
+	ErrCodeNotFound            ErrorCode = "NOT_FOUND"
+	ErrCodeInvalidSettings     ErrorCode = "INVALID_SETTINGS"
+	ErrCodeNotEnoughReviewers  ErrorCode = "NOT_ENOUGH_REVIEWERS"
+	ErrCodeNotEnoughApprovals  ErrorCode = "NOT_ENOUGH_APPROVALS"
+	ErrCodeInvalidTransition   ErrorCode = "INVALID_TRANSITION"
+	ErrCodePRNotOpen           ErrorCode = "PR_NOT_OPEN"
+	ErrCodeUnauthorized        ErrorCode = "UNAUTHORIZED"
+	ErrCodeForbidden           ErrorCode = "FORBIDDEN"
+	ErrCodeInvalidSignature    ErrorCode = "INVALID_SIGNATURE"
+	ErrCodeInvalidSubscription ErrorCode = "INVALID_SUBSCRIPTION"
 )
 
 type APIError struct {
@@ -16,6 +28,8 @@ type APIError struct {
 		Code    ErrorCode `json:"code"`
 		Message string    `json:"message"`
 	} `json:"error"`
+	// Explain - объяснение выбора ревьюверов, в котором не нашлось нужных кандидатов
+	Explain *entity.AssignmentExplanation `json:"explain,omitempty"`
 }
 
 func newAPIError(code ErrorCode, message string) APIError {
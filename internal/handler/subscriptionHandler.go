@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+	"PR-appointer/internal/service"
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SubscriptionHandler struct {
+	subscriptionService *service.SubscriptionService
+}
+
+func NewSubscriptionHandler(ctx context.Context, store *repository.Store) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		subscriptionService: service.NewSubscriptionService(store),
+	}
+}
+
+// AddSubscription godoc
+// @Summary Subscribe to outbound webhooks
+// @Description Register a URL that receives signed POST requests on pr.created, reviewer.assigned, reviewer.replaced, pr.merged, pr.ready, pr.closed, pr.reopened and user.status_changed events. An empty events list subscribes to all events. The body is signed with HMAC-SHA256 of the secret in X-Appointer-Signature-256
+// @Tags Subscriptions
+// @Accept json
+// @Produce json
+// @Param request body entity.SubscriptionCreateRequest true "Subscription data"
+// @Success 201 {object} entity.WebhookSubscription
+// @Failure 400 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 403 {object} APIError
+// @Security BearerAuth
+// @Router /subscriptions/add [post]
+func (h *SubscriptionHandler) AddSubscription(c *gin.Context) {
+	var req entity.SubscriptionCreateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
+		return
+	}
+
+	subscription, err := h.subscriptionService.Create(c.Request.Context(), &req)
+	if err != nil {
+		switch err.Error() {
+		case "invalid subscription url", "unknown event type":
+			c.JSON(http.StatusBadRequest, newAPIError(ErrCodeInvalidSubscription, err.Error()))
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"subscription": subscription})
+}
+
+// ListSubscriptions godoc
+// @Summary List outbound webhook subscriptions
+// @Description List subscriptions; secrets are not returned
+// @Tags Subscriptions
+// @Produce json
+// @Success 200 {array} entity.WebhookSubscription
+// @Failure 401 {object} APIError
+// @Failure 403 {object} APIError
+// @Security BearerAuth
+// @Router /subscriptions/list [get]
+func (h *SubscriptionHandler) ListSubscriptions(c *gin.Context) {
+	subscriptions, err := h.subscriptionService.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, newAPIError(ErrCodeNotFound, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"subscriptions": subscriptions})
+}
+
+// RemoveSubscription godoc
+// @Summary Remove outbound webhook subscription
+// @Description Remove subscription together with its delivery log
+// @Tags Subscriptions
+// @Accept json
+// @Produce json
+// @Param request body entity.SubscriptionRequest true "Subscription ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 404 {object} APIError
+// @Security BearerAuth
+// @Router /subscriptions/remove [post]
+func (h *SubscriptionHandler) RemoveSubscription(c *gin.Context) {
+	var req entity.SubscriptionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
+		return
+	}
+
+	if err := h.subscriptionService.Delete(c.Request.Context(), req.SubscriptionID); err != nil {
+		if err.Error() == "subscription not found" {
+			c.JSON(http.StatusNotFound, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, newAPIError(ErrCodeNotFound, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "removed"})
+}
+
+// ListDeliveries godoc
+// @Summary List outbound webhook deliveries
+// @Description List recent deliveries, newest first, with attempts, last response status and error
+// @Tags Subscriptions
+// @Produce json
+// @Param subscription_id query int false "Subscription ID"
+// @Param status query string false "Delivery status: pending, succeeded or failed"
+// @Param limit query int false "Maximum number of deliveries, up to 50"
+// @Success 200 {array} entity.WebhookDelivery
+// @Failure 400 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 404 {object} APIError
+// @Security BearerAuth
+// @Router /subscriptions/deliveries [get]
+func (h *SubscriptionHandler) ListDeliveries(c *gin.Context) {
+	filter := entity.DeliveryFilter{Status: c.Query("status")}
+	for name, target := range map[string]*int{"subscription_id": &filter.SubscriptionID, "limit": &filter.Limit} {
+		query := c.Query(name)
+		if query == "" {
+			continue
+		}
+		value, err := strconv.Atoi(query)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, name+" must be a number"))
+			return
+		}
+		*target = value
+	}
+
+	deliveries, err := h.subscriptionService.ListDeliveries(c.Request.Context(), filter)
+	if err != nil {
+		switch err.Error() {
+		case "subscription not found":
+			c.JSON(http.StatusNotFound, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		case "unknown delivery status":
+			c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+// Redeliver godoc
+// @Summary Replay outbound webhook delivery
+// @Description Queue a delivery again with a fresh attempt counter, e.g. after a failed delivery was fixed on the receiving side
+// @Tags Subscriptions
+// @Accept json
+// @Produce json
+// @Param request body entity.DeliveryRequest true "Delivery ID"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 403 {object} APIError
+// @Failure 404 {object} APIError
+// @Security BearerAuth
+// @Router /subscriptions/redeliver [post]
+func (h *SubscriptionHandler) Redeliver(c *gin.Context) {
+	var req entity.DeliveryRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
+		return
+	}
+
+	if err := h.subscriptionService.Redeliver(c.Request.Context(), req.DeliveryID); err != nil {
+		if err.Error() == "delivery not found" {
+			c.JSON(http.StatusNotFound, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, newAPIError(ErrCodeNotFound, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"status": "queued"})
+}
@@ -2,21 +2,22 @@ package handler
 
 import (
 	"PR-appointer/internal/entity"
+	"PR-appointer/internal/middleware"
+	"PR-appointer/internal/repository"
 	"PR-appointer/internal/service"
 	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type TeamHandler struct {
 	teamService *service.TeamService
 }
 
-func NewTeamHandler(ctx context.Context, db *pgxpool.Pool) *TeamHandler {
+func NewTeamHandler(ctx context.Context, store *repository.Store) *TeamHandler {
 	return &TeamHandler{
-		teamService: service.NewTeamService(db),
+		teamService: service.NewTeamService(store),
 	}
 }
 
@@ -29,6 +30,9 @@ func NewTeamHandler(ctx context.Context, db *pgxpool.Pool) *TeamHandler {
 // @Param request body entity.TeamCreateRequest true "Team data"
 // @Success 201 {object} entity.TeamResponse
 // @Failure 400 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 403 {object} APIError
+// @Security BearerAuth
 // @Router /team/add [post]
 func (h *TeamHandler) AddTeam(c *gin.Context) {
 	var req entity.TeamCreateRequest
@@ -60,6 +64,8 @@ func (h *TeamHandler) AddTeam(c *gin.Context) {
 // @Param team_name query string true "Team name"
 // @Success 200 {object} entity.TeamResponse
 // @Failure 404 {object} APIError
+// @Failure 401 {object} APIError
+// @Security BearerAuth
 // @Router /team/get [get]
 func (h *TeamHandler) GetTeam(c *gin.Context) {
 	teamName := c.Query("team_name")
@@ -76,3 +82,74 @@ func (h *TeamHandler) GetTeam(c *gin.Context) {
 
 	c.JSON(http.StatusOK, team)
 }
+
+// GetSettings godoc
+// @Summary Get team settings
+// @Description Get reviewer assignment settings of the team
+// @Tags Teams
+// @Produce json
+// @Param team_name query string true "Team name"
+// @Success 200 {object} entity.TeamSettingsResponse
+// @Failure 404 {object} APIError
+// @Failure 401 {object} APIError
+// @Security BearerAuth
+// @Router /team/settings [get]
+func (h *TeamHandler) GetSettings(c *gin.Context) {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, "team_name is required"))
+		return
+	}
+
+	settings, err := h.teamService.GetSettings(c.Request.Context(), teamName)
+	if err != nil {
+		c.JSON(http.StatusNotFound, newAPIError(ErrCodeNotFound, "team not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings godoc
+// @Summary Update team settings
+// @Description Update reviewer strategy, reviewers per PR, minimum reviewers, behavior when there are not enough candidates and fallback teams
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Param request body entity.TeamSettingsRequest true "Team settings"
+// @Success 200 {object} entity.TeamSettingsResponse
+// @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 403 {object} APIError
+// @Security BearerAuth
+// @Router /team/settings [post]
+func (h *TeamHandler) UpdateSettings(c *gin.Context) {
+	var req entity.TeamSettingsRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
+		return
+	}
+
+	// Тимлид меняет настройки только своей команды
+	if principal := middleware.PrincipalFrom(c); principal != nil && principal.Role == entity.RoleTeamLead {
+		isMember, err := h.teamService.IsMember(c.Request.Context(), req.TeamName, principal.UserID)
+		if err == nil && !isMember {
+			c.JSON(http.StatusForbidden, newAPIError(ErrCodeForbidden, "team lead can only change own team"))
+			return
+		}
+	}
+
+	settings, err := h.teamService.UpdateSettings(c.Request.Context(), &req)
+	if err != nil {
+		if err.Error() == "team not found" {
+			c.JSON(http.StatusNotFound, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeInvalidSettings, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
@@ -6,9 +6,10 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgxpool"
 
 	"PR-appointer/internal/entity"
+	"PR-appointer/internal/middleware"
+	"PR-appointer/internal/repository"
 	"PR-appointer/internal/service"
 )
 
@@ -16,9 +17,9 @@ type UserHandler struct {
 	userService *service.UserService
 }
 
-func NewUserHandler(ctx context.Context, db *pgxpool.Pool) *UserHandler {
+func NewUserHandler(ctx context.Context, store *repository.Store, seeds *service.SeedSource) *UserHandler {
 	return &UserHandler{
-		userService: service.NewUserService(db),
+		userService: service.NewUserService(store, seeds),
 	}
 }
 
@@ -31,6 +32,9 @@ func NewUserHandler(ctx context.Context, db *pgxpool.Pool) *UserHandler {
 // @Param request body entity.UserRequest true "User active status"
 // @Success 200 {object} entity.UserResponse
 // @Failure 404 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 403 {object} APIError
+// @Security BearerAuth
 // @Router /users/setIsActive [post]
 func (h *UserHandler) SetStatus(c *gin.Context) {
 	var req entity.UserRequest
@@ -49,17 +53,65 @@ func (h *UserHandler) SetStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"user": user})
 }
 
+// BulkDeactivate godoc
+// @Summary Deactivate users and reassign their open reviews
+// @Description Deactivate a list of users and/or all members of a team and, in one transaction, reassign their reviewer slots on OPEN PRs. Returns what moved and what could not be filled
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body entity.BulkDeactivateRequest true "Users to deactivate"
+// @Success 200 {object} entity.BulkDeactivateResponse
+// @Failure 400 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 403 {object} APIError
+// @Security BearerAuth
+// @Router /users/bulkDeactivate [post]
+func (h *UserHandler) BulkDeactivate(c *gin.Context) {
+	var req entity.BulkDeactivateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
+		return
+	}
+
+	report, err := h.userService.BulkDeactivate(c.Request.Context(), &req)
+	if err != nil {
+		switch err.Error() {
+		case "user not found", "team not found":
+			c.JSON(http.StatusNotFound, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		default:
+			c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
 // GetUserReviews godoc
 // @Summary Get PRs assigned to user as reviewer
-// @Description Get list of PRs where user is assigned as reviewer
+// @Description Get list of PRs where user is assigned as reviewer. Without user_id (or with user_id=me) returns the caller's reviews
 // @Tags Users
 // @Produce json
-// @Param user_id query string true "User ID"
+// @Param user_id query string false "User ID, or me for the caller"
 // @Success 200 {object} entity.UserReviewsResponse
+// @Failure 401 {object} APIError
+// @Security BearerAuth
 // @Router /users/getReview [get]
 func (h *UserHandler) GetUserReviews(c *gin.Context) {
-	userID, err := strconv.Atoi(c.Query("user_id"))
-	if err != nil {
+	var userID int
+	if query := c.Query("user_id"); query != "" && query != "me" {
+		id, err := strconv.Atoi(query)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, "user_id is required"))
+			return
+		}
+		userID = id
+	} else if principal := middleware.PrincipalFrom(c); principal != nil && principal.UserID != 0 {
+		userID = principal.UserID
+	} else {
 		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, "user_id is required"))
 		return
 	}
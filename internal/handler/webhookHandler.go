@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+	"PR-appointer/internal/service"
+)
+
+// maxWebhookBody - GitHub и GitLab присылают события до 25 МБ, но событиям PR столько не нужно
+const maxWebhookBody = 5 << 20
+
+// WebhookSecrets - секреты проверки вебхуков; пустой секрет отключает прием от провайдера.
+type WebhookSecrets struct {
+	GitHub string
+	GitLab string
+}
+
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+	secrets        WebhookSecrets
+}
+
+func NewWebhookHandler(ctx context.Context, store *repository.Store, secrets WebhookSecrets, seeds *service.SeedSource) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: service.NewWebhookService(store, seeds),
+		secrets:        secrets,
+	}
+}
+
+// GitHub godoc
+// @Summary Receive GitHub pull_request webhook
+// @Description Verifies X-Hub-Signature-256 and applies opened, ready_for_review, closed (merged or not) and reopened events to the PR linked to the GitHub repository and number. The PR author is the user whose username equals the GitHub login
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param X-GitHub-Event header string true "Event type"
+// @Param X-Hub-Signature-256 header string true "HMAC-SHA256 of the body"
+// @Success 200 {object} entity.WebhookResult
+// @Success 202 {object} entity.WebhookResult
+// @Failure 400 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 409 {object} APIError
+// @Router /webhooks/github [post]
+func (h *WebhookHandler) GitHub(c *gin.Context) {
+	body, ok := h.readBody(c)
+	if !ok {
+		return
+	}
+
+	if !validGitHubSignature(h.secrets.GitHub, body, c.GetHeader("X-Hub-Signature-256")) {
+		c.JSON(http.StatusUnauthorized, newAPIError(ErrCodeInvalidSignature, "invalid webhook signature"))
+		return
+	}
+
+	switch c.GetHeader("X-GitHub-Event") {
+	case "ping":
+		c.JSON(http.StatusOK, gin.H{"status": "pong"})
+		return
+	case "pull_request":
+	default:
+		c.JSON(http.StatusAccepted, entity.WebhookResult{Ignored: "unsupported event"})
+		return
+	}
+
+	var payload entity.GitHubPullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
+		return
+	}
+
+	event := &entity.ExternalPREvent{
+		Provider:    entity.ProviderGitHub,
+		Repository:  payload.Repository.FullName,
+		Number:      payload.Number,
+		Title:       payload.PullRequest.Title,
+		AuthorLogin: payload.PullRequest.User.Login,
+		Draft:       payload.PullRequest.Draft,
+	}
+	switch payload.Action {
+	case "opened":
+		event.Action = entity.ExternalActionOpened
+	case "ready_for_review":
+		event.Action = entity.ExternalActionReady
+	case "reopened":
+		event.Action = entity.ExternalActionReopened
+	case "closed":
+		event.Action = entity.ExternalActionClosed
+		if payload.PullRequest.Merged {
+			event.Action = entity.ExternalActionMerged
+		}
+	default:
+		c.JSON(http.StatusAccepted, entity.WebhookResult{Action: payload.Action, Ignored: "unsupported action"})
+		return
+	}
+
+	h.apply(c, event)
+}
+
+// GitLab godoc
+// @Summary Receive GitLab merge request webhook
+// @Description Verifies X-Gitlab-Token and applies Merge Request Hook events (open, draft removed, merge, close, reopen) to the PR linked to the GitLab project and MR iid. The PR author is the user whose username equals the GitLab username of whoever opened the MR
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param X-Gitlab-Event header string true "Event type"
+// @Param X-Gitlab-Token header string true "Webhook secret token"
+// @Success 200 {object} entity.WebhookResult
+// @Success 202 {object} entity.WebhookResult
+// @Failure 400 {object} APIError
+// @Failure 401 {object} APIError
+// @Failure 404 {object} APIError
+// @Failure 409 {object} APIError
+// @Router /webhooks/gitlab [post]
+func (h *WebhookHandler) GitLab(c *gin.Context) {
+	if !validGitLabToken(h.secrets.GitLab, c.GetHeader("X-Gitlab-Token")) {
+		c.JSON(http.StatusUnauthorized, newAPIError(ErrCodeInvalidSignature, "invalid webhook token"))
+		return
+	}
+
+	if c.GetHeader("X-Gitlab-Event") != "Merge Request Hook" {
+		c.JSON(http.StatusAccepted, entity.WebhookResult{Ignored: "unsupported event"})
+		return
+	}
+
+	body, ok := h.readBody(c)
+	if !ok {
+		return
+	}
+
+	var payload entity.GitLabMergeRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, err.Error()))
+		return
+	}
+
+	attrs := payload.ObjectAttributes
+	event := &entity.ExternalPREvent{
+		Provider:    entity.ProviderGitLab,
+		Repository:  payload.Project.PathWithNamespace,
+		Number:      attrs.IID,
+		Title:       attrs.Title,
+		AuthorLogin: payload.User.Username,
+		Draft:       attrs.Draft || attrs.WorkInProgress,
+	}
+	switch attrs.Action {
+	case "open":
+		event.Action = entity.ExternalActionOpened
+	case "reopen":
+		event.Action = entity.ExternalActionReopened
+	case "close":
+		event.Action = entity.ExternalActionClosed
+	case "merge":
+		event.Action = entity.ExternalActionMerged
+	case "update":
+		// Из обновлений важно только снятие статуса черновика
+		if draft := payload.Changes.Draft; draft != nil && draft.Previous && !draft.Current {
+			event.Action = entity.ExternalActionReady
+			break
+		}
+		fallthrough
+	default:
+		c.JSON(http.StatusAccepted, entity.WebhookResult{Action: attrs.Action, Ignored: "unsupported action"})
+		return
+	}
+
+	h.apply(c, event)
+}
+
+func (h *WebhookHandler) apply(c *gin.Context, event *entity.ExternalPREvent) {
+	if event.Repository == "" || event.Number == 0 {
+		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, "repository and PR number are required"))
+		return
+	}
+
+	result, err := h.webhookService.Apply(c.Request.Context(), event)
+	if err != nil {
+		switch err.Error() {
+		case "author not found", "author is not in any team", "external PR not found", "PR not found":
+			c.JSON(http.StatusNotFound, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		case "not enough active reviewers in team":
+			c.JSON(http.StatusConflict, newAPIError(ErrCodeNotEnoughReviewers, err.Error()))
+			return
+		case "PR already exists":
+			c.JSON(http.StatusConflict, newAPIError(ErrCodePRExists, err.Error()))
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, newAPIError(ErrCodeNotFound, err.Error()))
+			return
+		}
+	}
+
+	if result.Ignored != "" {
+		c.JSON(http.StatusAccepted, result)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *WebhookHandler) readBody(c *gin.Context) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBody))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, newAPIError(ErrCodeNotFound, "failed to read request body"))
+		return nil, false
+	}
+	return body, true
+}
+
+// validGitHubSignature сверяет заголовок "sha256=<hex>" с HMAC-SHA256 тела на секрете вебхука.
+func validGitHubSignature(secret string, body []byte, header string) bool {
+	signature, ok := strings.CutPrefix(header, "sha256=")
+	if secret == "" || !ok {
+		return false
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// validGitLabToken - GitLab передает секрет как есть, поэтому сравнение за постоянное время.
+func validGitLabToken(secret, token string) bool {
+	if secret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(token)) == 1
+}
@@ -0,0 +1,406 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/handler"
+	"PR-appointer/internal/service"
+	"PR-appointer/internal/storage"
+	"PR-appointer/internal/storage/storagetest"
+)
+
+const (
+	githubSecret = "github-secret"
+	gitlabToken  = "gitlab-token"
+)
+
+// Все события в testdata относятся к одному PR octo-org/backend, открытому alice
+var teamMembers = []string{"alice", "bob", "carol", "dave"}
+
+// newWebhookEngine поднимает обработчики вебхуков над чистым хранилищем.
+// Если withTeam, в хранилище есть команда с авторами событий из testdata.
+func newWebhookEngine(t *testing.T, withTeam bool) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	store := storagetest.Open(t, storage.DriverMemory)
+	if withTeam {
+		req := &entity.TeamCreateRequest{TeamName: "backend"}
+		for _, username := range teamMembers {
+			req.Members = append(req.Members, entity.TeamMemberRequest{Username: username, IsActive: true})
+		}
+		if _, err := service.NewTeamService(store).CreateTeam(context.Background(), req); err != nil {
+			t.Fatalf("create team: %v", err)
+		}
+	}
+
+	webhookHandler := handler.NewWebhookHandler(context.Background(), store, handler.WebhookSecrets{
+		GitHub: githubSecret,
+		GitLab: gitlabToken,
+	}, service.NewSeedSource(service.SeedConfig{Seed: 1}))
+
+	engine := gin.New()
+	engine.POST("/webhooks/github", webhookHandler.GitHub)
+	engine.POST("/webhooks/gitlab", webhookHandler.GitLab)
+	return engine
+}
+
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return body
+}
+
+func deliver(engine *gin.Engine, path string, headers map[string]string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		if value != "" {
+			req.Header.Set(name, value)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+// webhookResponse - поля ответа на доставку: результат WebhookResult или код ошибки APIError.
+type webhookResponse struct {
+	Status  string `json:"status"`
+	Ignored string `json:"ignored"`
+	Error   struct {
+		Code handler.ErrorCode `json:"code"`
+	} `json:"error"`
+}
+
+// expectResponse проверяет статус ответа, код ошибки, статус PR и причину пропуска события.
+func expectResponse(t *testing.T, rec *httptest.ResponseRecorder, status int, code handler.ErrorCode, prStatus, ignored string) {
+	t.Helper()
+
+	var resp webhookResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+	}
+
+	if rec.Code != status {
+		t.Errorf("status %d, want %d: %s", rec.Code, status, rec.Body.String())
+	}
+	if resp.Error.Code != code {
+		t.Errorf("error code %q, want %q", resp.Error.Code, code)
+	}
+	if resp.Status != prStatus {
+		t.Errorf("PR status %q, want %q", resp.Status, prStatus)
+	}
+	if resp.Ignored != ignored {
+		t.Errorf("ignored %q, want %q", resp.Ignored, ignored)
+	}
+}
+
+func githubSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func deliverGitHub(engine *gin.Engine, event, signature string, body []byte) *httptest.ResponseRecorder {
+	return deliver(engine, "/webhooks/github", map[string]string{
+		"X-GitHub-Event":      event,
+		"X-Hub-Signature-256": signature,
+	}, body)
+}
+
+func TestGitHubWebhook(t *testing.T) {
+	valid := func(body []byte) string { return githubSignature(githubSecret, body) }
+
+	tests := []struct {
+		name      string
+		noTeam    bool
+		before    []string
+		event     string
+		fixture   string
+		signature func(body []byte) string
+		status    int
+		code      handler.ErrorCode
+		prStatus  string
+		ignored   string
+	}{
+		{
+			name:    "opened draft",
+			fixture: "pull_request_opened.json",
+			status:  http.StatusOK, prStatus: entity.StatusDraft,
+		},
+		{
+			name:    "ready for review",
+			before:  []string{"pull_request_opened.json"},
+			fixture: "pull_request_ready_for_review.json",
+			status:  http.StatusOK, prStatus: entity.StatusOpen,
+		},
+		{
+			name:    "closed without merge",
+			before:  []string{"pull_request_opened.json", "pull_request_ready_for_review.json"},
+			fixture: "pull_request_closed.json",
+			status:  http.StatusOK, prStatus: entity.StatusClosed,
+		},
+		{
+			name:    "closed with merge",
+			before:  []string{"pull_request_opened.json", "pull_request_ready_for_review.json"},
+			fixture: "pull_request_merged.json",
+			status:  http.StatusOK, prStatus: entity.StatusMerged,
+		},
+		{
+			name:    "reopened",
+			before:  []string{"pull_request_opened.json", "pull_request_closed.json"},
+			fixture: "pull_request_reopened.json",
+			status:  http.StatusOK, prStatus: entity.StatusOpen,
+		},
+		{
+			name:    "duplicate opened",
+			before:  []string{"pull_request_opened.json"},
+			fixture: "pull_request_opened.json",
+			status:  http.StatusAccepted, ignored: "PR is already tracked",
+		},
+		{
+			name:    "duplicate closed",
+			before:  []string{"pull_request_opened.json", "pull_request_closed.json"},
+			fixture: "pull_request_closed.json",
+			status:  http.StatusAccepted, ignored: "PR is already in this state or cannot move to it",
+		},
+		{
+			name:    "untracked PR",
+			fixture: "pull_request_closed.json",
+			status:  http.StatusNotFound, code: handler.ErrCodeNotFound,
+		},
+		{
+			name:    "unknown login",
+			noTeam:  true,
+			fixture: "pull_request_opened.json",
+			status:  http.StatusNotFound, code: handler.ErrCodeNotFound,
+		},
+		{
+			name:    "ignored action",
+			before:  []string{"pull_request_opened.json"},
+			fixture: "pull_request_synchronize.json",
+			status:  http.StatusAccepted, ignored: "unsupported action",
+		},
+		{
+			name:    "ignored event",
+			event:   "issues",
+			fixture: "pull_request_opened.json",
+			status:  http.StatusAccepted, ignored: "unsupported event",
+		},
+		{
+			name:      "wrong secret",
+			fixture:   "pull_request_opened.json",
+			signature: func(body []byte) string { return githubSignature("other-secret", body) },
+			status:    http.StatusUnauthorized, code: handler.ErrCodeInvalidSignature,
+		},
+		{
+			name:      "signature of another body",
+			fixture:   "pull_request_opened.json",
+			signature: func([]byte) string { return githubSignature(githubSecret, []byte("{}")) },
+			status:    http.StatusUnauthorized, code: handler.ErrCodeInvalidSignature,
+		},
+		{
+			name:      "malformed signature",
+			fixture:   "pull_request_opened.json",
+			signature: func([]byte) string { return "sha256=not-hex" },
+			status:    http.StatusUnauthorized, code: handler.ErrCodeInvalidSignature,
+		},
+		{
+			name:      "missing signature",
+			fixture:   "pull_request_opened.json",
+			signature: func([]byte) string { return "" },
+			status:    http.StatusUnauthorized, code: handler.ErrCodeInvalidSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newWebhookEngine(t, !tt.noTeam)
+
+			for _, name := range tt.before {
+				body := fixture(t, name)
+				if rec := deliverGitHub(engine, "pull_request", valid(body), body); rec.Code != http.StatusOK {
+					t.Fatalf("deliver %s: status %d: %s", name, rec.Code, rec.Body.String())
+				}
+			}
+
+			event, signature := tt.event, tt.signature
+			if event == "" {
+				event = "pull_request"
+			}
+			if signature == nil {
+				signature = valid
+			}
+
+			body := fixture(t, tt.fixture)
+			rec := deliverGitHub(engine, event, signature(body), body)
+			expectResponse(t, rec, tt.status, tt.code, tt.prStatus, tt.ignored)
+		})
+	}
+}
+
+func TestGitHubWebhookPing(t *testing.T) {
+	engine := newWebhookEngine(t, false)
+
+	body := []byte(`{"zen":"Keep it logically awesome.","hook_id":482917}`)
+	rec := deliverGitHub(engine, "ping", githubSignature(githubSecret, body), body)
+	if rec.Code != http.StatusOK {
+		t.Errorf("ping: status %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func deliverGitLab(engine *gin.Engine, event, token string, body []byte) *httptest.ResponseRecorder {
+	return deliver(engine, "/webhooks/gitlab", map[string]string{
+		"X-Gitlab-Event": event,
+		"X-Gitlab-Token": token,
+	}, body)
+}
+
+func TestGitLabWebhook(t *testing.T) {
+	tests := []struct {
+		name     string
+		noTeam   bool
+		before   []string
+		event    string
+		fixture  string
+		token    *string
+		status   int
+		code     handler.ErrorCode
+		prStatus string
+		ignored  string
+	}{
+		{
+			name:    "open",
+			fixture: "merge_request_open.json",
+			status:  http.StatusOK, prStatus: entity.StatusOpen,
+		},
+		{
+			name:    "open draft",
+			fixture: "merge_request_open_draft.json",
+			status:  http.StatusOK, prStatus: entity.StatusDraft,
+		},
+		{
+			name:    "draft removed",
+			before:  []string{"merge_request_open_draft.json"},
+			fixture: "merge_request_draft_removed.json",
+			status:  http.StatusOK, prStatus: entity.StatusOpen,
+		},
+		{
+			name:    "close",
+			before:  []string{"merge_request_open.json"},
+			fixture: "merge_request_close.json",
+			status:  http.StatusOK, prStatus: entity.StatusClosed,
+		},
+		{
+			name:    "reopen",
+			before:  []string{"merge_request_open.json", "merge_request_close.json"},
+			fixture: "merge_request_reopen.json",
+			status:  http.StatusOK, prStatus: entity.StatusOpen,
+		},
+		{
+			name:    "merge",
+			before:  []string{"merge_request_open.json"},
+			fixture: "merge_request_merge.json",
+			status:  http.StatusOK, prStatus: entity.StatusMerged,
+		},
+		{
+			name:    "duplicate open",
+			before:  []string{"merge_request_open.json"},
+			fixture: "merge_request_open.json",
+			status:  http.StatusAccepted, ignored: "PR is already tracked",
+		},
+		{
+			name:    "duplicate merge",
+			before:  []string{"merge_request_open.json", "merge_request_merge.json"},
+			fixture: "merge_request_merge.json",
+			status:  http.StatusOK, prStatus: entity.StatusMerged,
+		},
+		{
+			name:    "untracked MR",
+			fixture: "merge_request_close.json",
+			status:  http.StatusNotFound, code: handler.ErrCodeNotFound,
+		},
+		{
+			name:    "unknown username",
+			noTeam:  true,
+			fixture: "merge_request_open.json",
+			status:  http.StatusNotFound, code: handler.ErrCodeNotFound,
+		},
+		{
+			name:    "ignored update",
+			before:  []string{"merge_request_open.json"},
+			fixture: "merge_request_update.json",
+			status:  http.StatusAccepted, ignored: "unsupported action",
+		},
+		{
+			name:    "ignored event",
+			event:   "Note Hook",
+			fixture: "merge_request_open.json",
+			status:  http.StatusAccepted, ignored: "unsupported event",
+		},
+		{
+			name:    "wrong token",
+			fixture: "merge_request_open.json",
+			token:   ptr("other-token"),
+			status:  http.StatusUnauthorized, code: handler.ErrCodeInvalidSignature,
+		},
+		{
+			name:    "token prefix",
+			fixture: "merge_request_open.json",
+			token:   ptr(gitlabToken[:len(gitlabToken)-1]),
+			status:  http.StatusUnauthorized, code: handler.ErrCodeInvalidSignature,
+		},
+		{
+			name:    "missing token",
+			fixture: "merge_request_open.json",
+			token:   ptr(""),
+			status:  http.StatusUnauthorized, code: handler.ErrCodeInvalidSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newWebhookEngine(t, !tt.noTeam)
+
+			for _, name := range tt.before {
+				if rec := deliverGitLab(engine, "Merge Request Hook", gitlabToken, fixture(t, name)); rec.Code != http.StatusOK {
+					t.Fatalf("deliver %s: status %d: %s", name, rec.Code, rec.Body.String())
+				}
+			}
+
+			event, token := tt.event, gitlabToken
+			if event == "" {
+				event = "Merge Request Hook"
+			}
+			if tt.token != nil {
+				token = *tt.token
+			}
+
+			rec := deliverGitLab(engine, event, token, fixture(t, tt.fixture))
+			expectResponse(t, rec, tt.status, tt.code, tt.prStatus, tt.ignored)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/service"
+)
+
+const principalKey = "principal"
+
+// Authenticate проверяет токен из заголовка Authorization: Bearer <token>
+// и кладет вызывающего в контекст запроса. При enabled=false пропускает все запросы.
+func Authenticate(authService *service.AuthService, enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled {
+			c.Next()
+			return
+		}
+
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok {
+			abortWithError(c, http.StatusUnauthorized, "UNAUTHORIZED", "missing bearer token")
+			return
+		}
+
+		principal, err := authService.Authenticate(c.Request.Context(), strings.TrimSpace(token))
+		if err != nil {
+			if err.Error() == "invalid token" {
+				abortWithError(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+				return
+			}
+			abortWithError(c, http.StatusInternalServerError, "INTERNAL", err.Error())
+			return
+		}
+
+		c.Set(principalKey, principal)
+		c.Request = c.Request.WithContext(service.WithActor(c.Request.Context(), principal.Actor()))
+		c.Next()
+	}
+}
+
+// RequireRole пропускает только вызывающих с одной из ролей roles.
+// Без аутентификации вызывающего нет, и проверка не выполняется.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := PrincipalFrom(c)
+		if principal != nil && !principal.HasRole(roles...) {
+			abortWithError(c, http.StatusForbidden, "FORBIDDEN", "insufficient permissions")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// PrincipalFrom возвращает вызывающего или nil, если аутентификация выключена.
+func PrincipalFrom(c *gin.Context) *entity.Principal {
+	principal, _ := c.Get(principalKey)
+	p, _ := principal.(*entity.Principal)
+	return p
+}
+
+// abortWithError отвечает в том же формате, что и обработчики
+func abortWithError(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, gin.H{"error": gin.H{"code": code, "message": message}})
+}
@@ -5,60 +5,133 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-type PRRepository struct {
+type PgPRRepository struct {
 	db *pgxpool.Pool
 }
 
-func NewPRRepository(db *pgxpool.Pool) *PRRepository {
-	return &PRRepository{db: db}
+func NewPgPRRepository(db *pgxpool.Pool) *PgPRRepository {
+	return &PgPRRepository{db: db}
 }
 
-func (r *PRRepository) Create(ctx context.Context, id int, title string, authorID int) (*entity.PullRequest, error) {
+func (r *PgPRRepository) conn(ctx context.Context) DBTX {
+	return executor(ctx, r.db)
+}
+
+func (r *PgPRRepository) Create(ctx context.Context, id int, title string, authorID, teamID int, status string, degraded bool) (*entity.PullRequest, error) {
+	if id == 0 {
+		return r.createWithSequenceID(ctx, title, authorID, teamID, status, degraded)
+	}
+
+	query := `
+		INSERT INTO pull_requests (id, title, author_id, team_id, status, degraded)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, title, author_id, team_id, status, created_at, updated_at, degraded
+	`
+
+	pr, err := r.insertPR(ctx, query, id, title, authorID, teamID, status, degraded)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, errors.New("PR already exists")
+		}
+		return nil, fmt.Errorf("failed to create PR: %w", err)
+	}
+
+	// Последовательность обгоняет id клиентов, чтобы PR без своего id не перебирали занятые значения
+	_, err = r.conn(ctx).Exec(ctx, `
+		SELECT setval('pull_requests_id_seq', $1)
+		WHERE $1 > (SELECT last_value FROM pull_requests_id_seq)
+	`, int64(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to advance PR id sequence: %w", err)
+	}
+
+	return pr, nil
+}
+
+// createWithSequenceID создает PR, у которого нет своего id (например, из вебхука), с id из pull_requests_id_seq.
+// Значения последовательности не повторяются, а id, уже занятые клиентами, пропускаются.
+func (r *PgPRRepository) createWithSequenceID(ctx context.Context, title string, authorID, teamID int, status string, degraded bool) (*entity.PullRequest, error) {
 	query := `
-		INSERT INTO pull_requests (id, title, author_id, status)
-		VALUES ($1, $2, $3, 'OPEN')
-		RETURNING id, title, author_id, status, created_at, updated_at
+		INSERT INTO pull_requests (id, title, author_id, team_id, status, degraded)
+		VALUES (nextval('pull_requests_id_seq'), $1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO NOTHING
+		RETURNING id, title, author_id, team_id, status, created_at, updated_at, degraded
 	`
 
+	for {
+		pr, err := r.insertPR(ctx, query, title, authorID, teamID, status, degraded)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create PR: %w", err)
+		}
+		return pr, nil
+	}
+}
+
+func (r *PgPRRepository) insertPR(ctx context.Context, query string, args ...any) (*entity.PullRequest, error) {
 	pr := entity.PullRequest{}
-	err := r.db.QueryRow(ctx, query, id, title, authorID).Scan(
+	err := r.conn(ctx).QueryRow(ctx, query, args...).Scan(
 		&pr.ID,
 		&pr.Title,
 		&pr.AuthorID,
+		&pr.TeamID,
 		&pr.Status,
 		&pr.CreatedAt,
 		&pr.UpdatedAt,
+		&pr.Degraded,
 	)
-
 	if err != nil {
-		fmt.Println(err.Error())
-		return nil, fmt.Errorf("failed to create PR: %w", err)
+		return nil, err
 	}
 
 	return &pr, nil
 }
 
-func (r *PRRepository) GetByID(ctx context.Context, prID int) (*entity.PullRequest, error) {
+func (r *PgPRRepository) GetByID(ctx context.Context, prID int) (*entity.PullRequest, error) {
 	query := `
-		SELECT id, title, author_id, status, created_at, updated_at, merged_at
+		SELECT id, title, author_id, team_id, status, created_at, updated_at, merged_at, closed_at, degraded
 		FROM pull_requests
 		WHERE id = $1
 	`
 
+	return r.getPR(ctx, query, prID)
+}
+
+// GetByIDForUpdate читает PR и блокирует его строку до конца транзакции:
+// конкурирующие изменения одного PR выполняются по очереди.
+func (r *PgPRRepository) GetByIDForUpdate(ctx context.Context, prID int) (*entity.PullRequest, error) {
+	query := `
+		SELECT id, title, author_id, team_id, status, created_at, updated_at, merged_at, closed_at, degraded
+		FROM pull_requests
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	return r.getPR(ctx, query, prID)
+}
+
+func (r *PgPRRepository) getPR(ctx context.Context, query string, prID int) (*entity.PullRequest, error) {
 	pr := entity.PullRequest{}
-	err := r.db.QueryRow(ctx, query, prID).Scan(
+	err := r.conn(ctx).QueryRow(ctx, query, prID).Scan(
 		&pr.ID,
 		&pr.Title,
 		&pr.AuthorID,
+		&pr.TeamID,
 		&pr.Status,
 		&pr.CreatedAt,
 		&pr.UpdatedAt,
 		&pr.MergedAt,
+		&pr.ClosedAt,
+		&pr.Degraded,
 	)
 
 	if err != nil {
@@ -71,23 +144,28 @@ func (r *PRRepository) GetByID(ctx context.Context, prID int) (*entity.PullReque
 	return &pr, nil
 }
 
-func (r *PRRepository) UpdateStatus(ctx context.Context, prID int, status string) (*entity.PullRequest, error) {
+func (r *PgPRRepository) UpdateStatus(ctx context.Context, prID int, status string) (*entity.PullRequest, error) {
 	query := `
 		UPDATE pull_requests
-		SET status = $1, updated_at = CURRENT_TIMESTAMP, merged_at = CURRENT_TIMESTAMP
+		SET status = $1, updated_at = CURRENT_TIMESTAMP,
+			merged_at = CASE WHEN $1 = 'MERGED' THEN CURRENT_TIMESTAMP ELSE merged_at END,
+			closed_at = CASE WHEN $1 = 'CLOSED' THEN CURRENT_TIMESTAMP ELSE NULL END
 		WHERE id = $2
-		RETURNING id, title, author_id, status, created_at, updated_at, merged_at
+		RETURNING id, title, author_id, team_id, status, created_at, updated_at, merged_at, closed_at, degraded
 	`
 
 	pr := entity.PullRequest{}
-	err := r.db.QueryRow(ctx, query, status, prID).Scan(
+	err := r.conn(ctx).QueryRow(ctx, query, status, prID).Scan(
 		&pr.ID,
 		&pr.Title,
 		&pr.AuthorID,
+		&pr.TeamID,
 		&pr.Status,
 		&pr.CreatedAt,
 		&pr.UpdatedAt,
 		&pr.MergedAt,
+		&pr.ClosedAt,
+		&pr.Degraded,
 	)
 
 	if err != nil {
@@ -100,27 +178,45 @@ func (r *PRRepository) UpdateStatus(ctx context.Context, prID int, status string
 	return &pr, nil
 }
 
-func (r *PRRepository) AddReviewer(ctx context.Context, prID int, reviewerID int) error {
+func (r *PgPRRepository) SetDegraded(ctx context.Context, prID int, degraded bool) error {
 	query := `
-		INSERT INTO pr_reviewers (pr_id, reviewer_id)
-		VALUES ($1, $2)
+		UPDATE pull_requests
+		SET degraded = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
 	`
 
-	_, err := r.db.Exec(ctx, query, prID, reviewerID)
+	_, err := r.conn(ctx).Exec(ctx, query, prID, degraded)
 	if err != nil {
+		return fmt.Errorf("failed to update PR degraded flag: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PgPRRepository) AddReviewer(ctx context.Context, prID int, reviewerID, teamID int, crossTeam bool) error {
+	query := `
+		INSERT INTO pr_reviewers (pr_id, reviewer_id, team_id, cross_team)
+		VALUES ($1, $2, NULLIF($3, 0), $4)
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query, prID, reviewerID, teamID, crossTeam)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return errors.New("reviewer is already assigned to this PR")
+		}
 		return fmt.Errorf("failed to add reviewer: %w", err)
 	}
 
 	return nil
 }
 
-func (r *PRRepository) RemoveReviewer(ctx context.Context, prID int, reviewerID int) error {
+func (r *PgPRRepository) RemoveReviewer(ctx context.Context, prID int, reviewerID int) error {
 	query := `
 		DELETE FROM pr_reviewers
 		WHERE pr_id = $1 AND reviewer_id = $2
 	`
 
-	_, err := r.db.Exec(ctx, query, prID, reviewerID)
+	_, err := r.conn(ctx).Exec(ctx, query, prID, reviewerID)
 	if err != nil {
 		return fmt.Errorf("failed to remove reviewer: %w", err)
 	}
@@ -128,26 +224,46 @@ func (r *PRRepository) RemoveReviewer(ctx context.Context, prID int, reviewerID
 	return nil
 }
 
-func (r *PRRepository) GetReviewers(ctx context.Context, prID int) ([]entity.UserResponse, error) {
+func (r *PgPRRepository) RemoveAllReviewers(ctx context.Context, prID int) error {
 	query := `
-		SELECT u.id, u.username, u.is_active, t.name
-		FROM users u
-		JOIN pr_reviewers pr ON u.id = pr.reviewer_id
-		JOIN team_members on u.id = team_members.user_id
-		JOIN teams t on team_members.team_id = t.id
-		WHERE pr.pr_id = $1
-		ORDER BY pr.assigned_at
+		DELETE FROM pr_reviewers
+		WHERE pr_id = $1
 	`
 
-	rows, err := r.db.Query(ctx, query, prID)
+	_, err := r.conn(ctx).Exec(ctx, query, prID)
+	if err != nil {
+		return fmt.Errorf("failed to remove reviewers: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PgPRRepository) GetReviewers(ctx context.Context, prID int) ([]entity.ReviewerResponse, error) {
+	query := `
+		SELECT u.id, u.username, u.is_active, COALESCE(t.id, 0), COALESCE(t.name, ''), prr.cross_team,
+			COALESCE(prr.verdict, ''), prr.reviewed_at, prr.assigned_at
+		FROM pr_reviewers prr
+		JOIN users u ON u.id = prr.reviewer_id
+		LEFT JOIN teams t ON t.id = COALESCE(prr.team_id, (
+			-- Для старых назначений без команды берем основную команду ревьювера
+			SELECT tm.team_id FROM team_members tm
+			WHERE tm.user_id = u.id
+			ORDER BY tm.is_primary DESC, tm.team_id
+			LIMIT 1
+		))
+		WHERE prr.pr_id = $1
+		ORDER BY prr.assigned_at
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, prID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query reviewers: %w", err)
 	}
 
-	return ScanUserResponses(ctx, rows) // Используем общую функцию
+	return ScanReviewerResponses(ctx, rows) // Используем общую функцию
 }
 
-func (r *PRRepository) IsReviewerAssigned(ctx context.Context, prID int, reviewerID int) (bool, error) {
+func (r *PgPRRepository) IsReviewerAssigned(ctx context.Context, prID int, reviewerID int) (bool, error) {
 	query := `
 		SELECT EXISTS(
 			SELECT 1 FROM pr_reviewers 
@@ -156,7 +272,7 @@ func (r *PRRepository) IsReviewerAssigned(ctx context.Context, prID int, reviewe
 	`
 
 	var exists bool
-	err := r.db.QueryRow(ctx, query, prID, reviewerID).Scan(&exists)
+	err := r.conn(ctx).QueryRow(ctx, query, prID, reviewerID).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("failed to check reviewer assignment: %w", err)
 	}
@@ -164,31 +280,31 @@ func (r *PRRepository) IsReviewerAssigned(ctx context.Context, prID int, reviewe
 	return exists, nil
 }
 
-func (r *PRRepository) GetPRsByReviewer(ctx context.Context, reviewerID int) ([]entity.PullRequest, error) {
+func (r *PgPRRepository) GetPRsByReviewer(ctx context.Context, reviewerID int) ([]entity.PRSummary, error) {
 	query := `
-		SELECT DISTINCT pr.id, pr.title, pr.author_id, pr.status, pr.created_at, pr.updated_at
+		SELECT pr.id, pr.title, pr.author_id, pr.status, COALESCE(prr.verdict, ''), prr.reviewed_at
 		FROM pull_requests pr
 		INNER JOIN pr_reviewers prr ON pr.id = prr.pr_id
 		WHERE prr.reviewer_id = $1
 		ORDER BY pr.created_at DESC
 	`
 
-	rows, err := r.db.Query(ctx, query, reviewerID)
+	rows, err := r.conn(ctx).Query(ctx, query, reviewerID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query PRs by reviewer: %w", err)
 	}
 	defer rows.Close()
 
-	var prs []entity.PullRequest
+	var prs []entity.PRSummary
 	for rows.Next() {
-		pr := entity.PullRequest{}
+		pr := entity.PRSummary{}
 		err := rows.Scan(
-			&pr.ID,
-			&pr.Title,
+			&pr.PullRequestID,
+			&pr.PullRequestName,
 			&pr.AuthorID,
 			&pr.Status,
-			&pr.CreatedAt,
-			&pr.UpdatedAt,
+			&pr.Verdict,
+			&pr.ReviewedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan PR: %w", err)
@@ -198,3 +314,236 @@ func (r *PRRepository) GetPRsByReviewer(ctx context.Context, reviewerID int) ([]
 
 	return prs, nil
 }
+
+func (r *PgPRRepository) SetVerdict(ctx context.Context, prID, reviewerID int, verdict string) error {
+	query := `
+		UPDATE pr_reviewers
+		SET verdict = $3, reviewed_at = CURRENT_TIMESTAMP
+		WHERE pr_id = $1 AND reviewer_id = $2
+	`
+
+	tag, err := r.conn(ctx).Exec(ctx, query, prID, reviewerID, verdict)
+	if err != nil {
+		return fmt.Errorf("failed to set verdict: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New("reviewer is not assigned to this PR")
+	}
+
+	return nil
+}
+
+func (r *PgPRRepository) CountApprovals(ctx context.Context, prID int) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM pr_reviewers
+		WHERE pr_id = $1 AND verdict = 'APPROVED'
+	`
+
+	var count int
+	err := r.conn(ctx).QueryRow(ctx, query, prID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count approvals: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *PgPRRepository) GetLastAssignedAt(ctx context.Context, reviewerIDs []int) (map[int]time.Time, error) {
+	query := `
+		SELECT reviewer_id, MAX(assigned_at)
+		FROM pr_reviewers
+		WHERE reviewer_id = ANY($1)
+		GROUP BY reviewer_id
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, reviewerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query last assignments: %w", err)
+	}
+	defer rows.Close()
+
+	lastAssigned := make(map[int]time.Time)
+	for rows.Next() {
+		var reviewerID int
+		var assignedAt time.Time
+		if err := rows.Scan(&reviewerID, &assignedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan last assignment: %w", err)
+		}
+		lastAssigned[reviewerID] = assignedAt
+	}
+
+	return lastAssigned, nil
+}
+
+func (r *PgPRRepository) GetOpenReviewCounts(ctx context.Context, reviewerIDs []int) (map[int]int, error) {
+	query := `
+		SELECT prr.reviewer_id, COUNT(*)
+		FROM pr_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pr_id
+		WHERE prr.reviewer_id = ANY($1) AND pr.status = 'OPEN'
+		GROUP BY prr.reviewer_id
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, reviewerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open review counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int]int)
+	for rows.Next() {
+		var reviewerID, count int
+		if err := rows.Scan(&reviewerID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan open review count: %w", err)
+		}
+		counts[reviewerID] = count
+	}
+
+	return counts, nil
+}
+
+func (r *PgPRRepository) GetOpenAssignments(ctx context.Context, reviewerIDs []int) ([]entity.ReviewAssignment, error) {
+	query := `
+		SELECT prr.pr_id, prr.reviewer_id
+		FROM pr_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pr_id
+		WHERE prr.reviewer_id = ANY($1) AND pr.status = 'OPEN'
+		ORDER BY prr.pr_id, prr.reviewer_id
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, reviewerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open assignments: %w", err)
+	}
+	defer rows.Close()
+
+	var assignments []entity.ReviewAssignment
+	for rows.Next() {
+		assignment := entity.ReviewAssignment{}
+		if err := rows.Scan(&assignment.PullRequestID, &assignment.ReviewerID); err != nil {
+			return nil, fmt.Errorf("failed to scan assignment: %w", err)
+		}
+		assignments = append(assignments, assignment)
+	}
+
+	return assignments, nil
+}
+
+// prSortColumns - колонки pull_requests для допустимых полей сортировки списка PR.
+var prSortColumns = map[string]string{
+	entity.PRSortCreatedAt: "created_at",
+	entity.PRSortUpdatedAt: "updated_at",
+	entity.PRSortTitle:     "title",
+	entity.PRSortID:        "id",
+}
+
+// prListOrder возвращает колонку сортировки, направление и оператор сравнения с курсором.
+// При равенстве значений порядок задает id, поэтому страницы не пересекаются.
+func prListOrder(filter entity.PRListFilter) (column, direction, cmp string, err error) {
+	column, ok := prSortColumns[filter.Sort]
+	if !ok {
+		return "", "", "", fmt.Errorf("unknown sort field %q", filter.Sort)
+	}
+	if filter.Desc {
+		return column, "DESC", "<", nil
+	}
+	return column, "ASC", ">", nil
+}
+
+// prListAfter - значение поля сортировки из курсора для сравнения с колонкой prListOrder.
+func prListAfter(filter entity.PRListFilter) any {
+	switch filter.Sort {
+	case entity.PRSortCreatedAt, entity.PRSortUpdatedAt:
+		return filter.After.Time
+	case entity.PRSortTitle:
+		return filter.After.Title
+	default:
+		return filter.After.ID
+	}
+}
+
+// likePattern - шаблон LIKE для поиска подстроки, символы % и _ в ней экранируются.
+func likePattern(substring string) string {
+	if substring == "" {
+		return ""
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(substring)
+	return "%" + escaped + "%"
+}
+
+func (r *PgPRRepository) List(ctx context.Context, filter entity.PRListFilter) ([]entity.PRListItem, error) {
+	column, direction, cmp, err := prListOrder(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Границы интервалов сравниваются как timestamptz: в timestamp без часового пояса pgx передал бы
+	// время на часах смещения, а не момент
+	args := []any{
+		filter.Status,
+		filter.AuthorID,
+		filter.ReviewerID,
+		filter.TeamID,
+		likePattern(filter.Title),
+		filter.CreatedFrom,
+		filter.CreatedTo,
+		filter.MergedFrom,
+		filter.MergedTo,
+		filter.Limit,
+	}
+
+	after := "TRUE"
+	if filter.After != nil {
+		after = fmt.Sprintf("(pr.%s, pr.id) %s ($11, $12)", column, cmp)
+		args = append(args, prListAfter(filter), filter.After.ID)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT pr.id, pr.title, pr.author_id, COALESCE(t.name, ''), pr.status, pr.degraded,
+			pr.created_at, pr.updated_at, pr.merged_at, pr.closed_at
+		FROM pull_requests pr
+		LEFT JOIN teams t ON t.id = pr.team_id
+		WHERE ($1 = '' OR pr.status = $1)
+			AND ($2 = 0 OR pr.author_id = $2)
+			AND ($3 = 0 OR EXISTS (SELECT 1 FROM pr_reviewers prr WHERE prr.pr_id = pr.id AND prr.reviewer_id = $3))
+			AND ($4 = 0 OR pr.team_id = $4)
+			AND ($5 = '' OR pr.title ILIKE $5)
+			AND ($6::timestamptz IS NULL OR pr.created_at >= $6::timestamptz)
+			AND ($7::timestamptz IS NULL OR pr.created_at < $7::timestamptz)
+			AND ($8::timestamptz IS NULL OR pr.merged_at >= $8::timestamptz)
+			AND ($9::timestamptz IS NULL OR pr.merged_at < $9::timestamptz)
+			AND %[3]s
+		ORDER BY pr.%[1]s %[2]s, pr.id %[2]s
+		LIMIT $10
+	`, column, direction, after)
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PRs: %w", err)
+	}
+	defer rows.Close()
+
+	prs := []entity.PRListItem{}
+	for rows.Next() {
+		var pr entity.PRListItem
+		if err := rows.Scan(
+			&pr.PullRequestID,
+			&pr.PullRequestName,
+			&pr.AuthorID,
+			&pr.TeamName,
+			&pr.Status,
+			&pr.Degraded,
+			&pr.CreatedAt,
+			&pr.UpdatedAt,
+			&pr.MergedAt,
+			&pr.ClosedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan PR: %w", err)
+		}
+		prs = append(prs, pr)
+	}
+
+	return prs, rows.Err()
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"PR-appointer/internal/entity"
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgAuditRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPgAuditRepository(db *pgxpool.Pool) *PgAuditRepository {
+	return &PgAuditRepository{db: db}
+}
+
+func (r *PgAuditRepository) conn(ctx context.Context) DBTX {
+	return executor(ctx, r.db)
+}
+
+func (r *PgAuditRepository) Add(ctx context.Context, entry *entity.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (actor, action, pr_id, user_id, team_id, before_state, after_state, reason)
+		VALUES ($1, $2, NULLIF($3, 0), NULLIF($4, 0), NULLIF($5, 0), $6, $7, NULLIF($8, ''))
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query,
+		entry.Actor,
+		entry.Action,
+		entry.PullRequestID,
+		entry.UserID,
+		entry.TeamID,
+		nullJSON(entry.Before),
+		nullJSON(entry.After),
+		entry.Reason,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// List возвращает записи, новые первыми.
+func (r *PgAuditRepository) List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error) {
+	query := `
+		SELECT id, actor, action, COALESCE(pr_id, 0), COALESCE(user_id, 0), COALESCE(team_id, 0),
+			before_state, after_state, COALESCE(reason, ''), created_at
+		FROM audit_log
+		WHERE ($1 = 0 OR pr_id = $1)
+			AND ($2 = 0 OR user_id = $2)
+			AND ($3 = 0 OR team_id = $3)
+			AND ($4::timestamp IS NULL OR created_at >= $4)
+			AND ($5::timestamp IS NULL OR created_at < $5)
+		ORDER BY id DESC
+		LIMIT $6
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query,
+		filter.PullRequestID,
+		filter.UserID,
+		filter.TeamID,
+		filter.From,
+		filter.To,
+		filter.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []entity.AuditEntry{}
+	for rows.Next() {
+		var e entity.AuditEntry
+		if err := rows.Scan(
+			&e.ID,
+			&e.Actor,
+			&e.Action,
+			&e.PullRequestID,
+			&e.UserID,
+			&e.TeamID,
+			&e.Before,
+			&e.After,
+			&e.Reason,
+			&e.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// nullJSON - пустое состояние пишется как NULL, а не как пустая строка JSON
+func nullJSON(state []byte) any {
+	if len(state) == 0 {
+		return nil
+	}
+	return state
+}
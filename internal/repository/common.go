@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 
 	"PR-appointer/internal/entity"
 )
@@ -29,3 +31,35 @@ func ScanUserResponses(ctx context.Context, rows pgx.Rows) ([]entity.UserRespons
 
 	return users, nil
 }
+
+func ScanReviewerResponses(ctx context.Context, rows pgx.Rows) ([]entity.ReviewerResponse, error) {
+	defer rows.Close()
+
+	var reviewers []entity.ReviewerResponse
+	for rows.Next() {
+		reviewer := entity.ReviewerResponse{}
+		err := rows.Scan(
+			&reviewer.UserID,
+			&reviewer.Username,
+			&reviewer.IsActive,
+			&reviewer.TeamID,
+			&reviewer.TeamName,
+			&reviewer.CrossTeam,
+			&reviewer.Verdict,
+			&reviewer.ReviewedAt,
+			&reviewer.AssignedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reviewer: %w", err)
+		}
+		reviewers = append(reviewers, reviewer)
+	}
+
+	return reviewers, nil
+}
+
+// isUniqueViolation сообщает, что запрос нарушил ограничение уникальности.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
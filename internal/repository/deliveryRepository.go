@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"PR-appointer/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const deliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts,
+	next_attempt_at, response_status, last_error, created_at, delivered_at`
+
+type PgDeliveryRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPgDeliveryRepository(db *pgxpool.Pool) *PgDeliveryRepository {
+	return &PgDeliveryRepository{db: db}
+}
+
+func (r *PgDeliveryRepository) conn(ctx context.Context) DBTX {
+	return executor(ctx, r.db)
+}
+
+// Enqueue ставит событие в очередь подписки; повторная постановка того же события ничего не меняет.
+func (r *PgDeliveryRepository) Enqueue(ctx context.Context, subscriptionID int, eventID, eventType string, payload []byte) error {
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, subscriptionID, eventID, eventType, payload); err != nil {
+		return fmt.Errorf("failed to enqueue delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDue забирает доставки, которые пора отправить, и откладывает их на lease:
+// другие реплики их не возьмут, а если процесс упадет до записи результата, доставка повторится после lease.
+func (r *PgDeliveryRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY next_attempt_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns
+
+	rows, err := r.conn(ctx).Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+
+	return scanDeliveries(rows)
+}
+
+func (r *PgDeliveryRepository) RecordAttempt(ctx context.Context, deliveryID int, attempt entity.DeliveryAttempt) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1,
+			status = $2,
+			response_status = NULLIF($3, 0),
+			last_error = NULLIF($4, ''),
+			next_attempt_at = CASE WHEN $2 = 'pending' THEN CURRENT_TIMESTAMP + make_interval(secs => $5) END,
+			delivered_at = CASE WHEN $2 = 'succeeded' THEN CURRENT_TIMESTAMP END
+		WHERE id = $1
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query, deliveryID, deliveryStatus(attempt), attempt.ResponseStatus, attempt.Error, attempt.RetryIn.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to record delivery attempt: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PgDeliveryRepository) List(ctx context.Context, filter entity.DeliveryFilter) ([]entity.WebhookDelivery, error) {
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries
+		WHERE ($1 = 0 OR subscription_id = $1) AND ($2 = '' OR status = $2)
+		ORDER BY id DESC
+		LIMIT $3
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, filter.SubscriptionID, filter.Status, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+
+	return scanDeliveries(rows)
+}
+
+// Redeliver ставит доставку в очередь заново с полным числом попыток.
+func (r *PgDeliveryRepository) Redeliver(ctx context.Context, deliveryID int) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = CURRENT_TIMESTAMP, last_error = NULL
+		WHERE id = $1
+	`
+
+	tag, err := r.conn(ctx).Exec(ctx, query, deliveryID)
+	if err != nil {
+		return fmt.Errorf("failed to redeliver: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New("delivery not found")
+	}
+
+	return nil
+}
+
+func scanDeliveries(rows pgx.Rows) ([]entity.WebhookDelivery, error) {
+	defer rows.Close()
+
+	deliveries := []entity.WebhookDelivery{}
+	for rows.Next() {
+		var d entity.WebhookDelivery
+		if err := rows.Scan(
+			&d.ID,
+			&d.SubscriptionID,
+			&d.EventID,
+			&d.EventType,
+			&d.Payload,
+			&d.Status,
+			&d.Attempts,
+			&d.NextAttemptAt,
+			&d.ResponseStatus,
+			&d.LastError,
+			&d.CreatedAt,
+			&d.DeliveredAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// deliveryStatus - статус доставки после попытки.
+func deliveryStatus(attempt entity.DeliveryAttempt) string {
+	switch {
+	case attempt.Succeeded:
+		return entity.DeliverySucceeded
+	case attempt.RetryIn > 0:
+		return entity.DeliveryPending
+	default:
+		return entity.DeliveryFailed
+	}
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgExternalPRRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPgExternalPRRepository(db *pgxpool.Pool) *PgExternalPRRepository {
+	return &PgExternalPRRepository{db: db}
+}
+
+func (r *PgExternalPRRepository) conn(ctx context.Context) DBTX {
+	return executor(ctx, r.db)
+}
+
+func (r *PgExternalPRRepository) GetPRID(ctx context.Context, provider, repository string, number int) (int, error) {
+	query := `
+		SELECT pr_id FROM external_pull_requests
+		WHERE provider = $1 AND repository = $2 AND number = $3
+	`
+
+	var prID int
+	err := r.conn(ctx).QueryRow(ctx, query, provider, repository, number).Scan(&prID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, errors.New("external PR not found")
+		}
+		return 0, fmt.Errorf("failed to get external PR: %w", err)
+	}
+
+	return prID, nil
+}
+
+func (r *PgExternalPRRepository) Link(ctx context.Context, provider, repository string, number, prID int) error {
+	query := `
+		INSERT INTO external_pull_requests (provider, repository, number, pr_id)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, provider, repository, number, prID); err != nil {
+		if isUniqueViolation(err) {
+			return errors.New("external PR already linked")
+		}
+		return fmt.Errorf("failed to link external PR: %w", err)
+	}
+
+	return nil
+}
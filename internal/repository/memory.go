@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"PR-appointer/internal/entity"
+)
+
+// memoryDB - хранилище в памяти для тестов и локального запуска без базы.
+// Все операции выполняются под одной блокировкой, транзакция держит ее до конца
+// и при ошибке восстанавливает снимок данных.
+type memoryDB struct {
+	mu   sync.Mutex
+	data *memoryData
+}
+
+type memoryData struct {
+	users      map[int]entity.User
+	nextUserID int
+	teams      map[int]entity.Team
+	nextTeamID int
+	members    []memoryMember
+	settings   map[int]entity.TeamSettings
+	fallbacks  map[int][]int
+	prs        map[int]entity.PullRequest
+	// Порядок назначений совпадает с порядком assigned_at
+	reviewers   []memoryReviewer
+	tokens      map[int]entity.APIToken
+	nextTokenID int
+	externalPRs map[memoryExternalKey]int
+
+	subscriptions      map[int]entity.WebhookSubscription
+	nextSubscriptionID int
+	deliveries         map[int]entity.WebhookDelivery
+	nextDeliveryID     int
+
+	outbox       map[int]memoryOutboxEvent
+	nextOutboxID int
+
+	// audit только дополняется, поэтому id записи - ее номер
+	audit []entity.AuditEntry
+}
+
+type memoryExternalKey struct {
+	provider   string
+	repository string
+	number     int
+}
+
+type memoryMember struct {
+	teamID       int
+	userID       int
+	reviewWeight int
+	isPrimary    bool
+}
+
+type memoryReviewer struct {
+	prID       int
+	reviewerID int
+	teamID     int
+	crossTeam  bool
+	verdict    string
+	reviewedAt *time.Time
+	assignedAt time.Time
+}
+
+type memoryTxKey struct{}
+
+// NewMemoryStore возвращает пустое хранилище в памяти.
+func NewMemoryStore() *Store {
+	db := &memoryDB{
+		data: &memoryData{
+			users:       make(map[int]entity.User),
+			teams:       make(map[int]entity.Team),
+			settings:    make(map[int]entity.TeamSettings),
+			fallbacks:   make(map[int][]int),
+			prs:         make(map[int]entity.PullRequest),
+			tokens:      make(map[int]entity.APIToken),
+			externalPRs: make(map[memoryExternalKey]int),
+
+			subscriptions: make(map[int]entity.WebhookSubscription),
+			deliveries:    make(map[int]entity.WebhookDelivery),
+
+			outbox: make(map[int]memoryOutboxEvent),
+		},
+	}
+
+	return &Store{
+		PRs:           &memoryPRRepository{db: db},
+		Users:         &memoryUserRepository{db: db},
+		Teams:         &memoryTeamRepository{db: db},
+		Tokens:        &memoryTokenRepository{db: db},
+		ExternalPRs:   &memoryExternalPRRepository{db: db},
+		Subscriptions: &memorySubscriptionRepository{db: db},
+		Deliveries:    &memoryDeliveryRepository{db: db},
+		Outbox:        &memoryOutboxRepository{db: db},
+		Audit:         &memoryAuditRepository{db: db},
+		UoW:           &memoryUnitOfWork{db: db},
+	}
+}
+
+// lock захватывает хранилище на время одной операции.
+// Внутри транзакции блокировка уже захвачена и повторно не берется.
+func (db *memoryDB) lock(ctx context.Context) func() {
+	if tx, ok := ctx.Value(memoryTxKey{}).(*memoryDB); ok && tx == db {
+		return func() {}
+	}
+
+	db.mu.Lock()
+	return db.mu.Unlock
+}
+
+func (d *memoryData) clone() *memoryData {
+	fallbacks := make(map[int][]int, len(d.fallbacks))
+	for teamID, ids := range d.fallbacks {
+		fallbacks[teamID] = slices.Clone(ids)
+	}
+
+	return &memoryData{
+		users:       maps.Clone(d.users),
+		nextUserID:  d.nextUserID,
+		teams:       maps.Clone(d.teams),
+		nextTeamID:  d.nextTeamID,
+		members:     slices.Clone(d.members),
+		settings:    maps.Clone(d.settings),
+		fallbacks:   fallbacks,
+		prs:         maps.Clone(d.prs),
+		reviewers:   slices.Clone(d.reviewers),
+		tokens:      maps.Clone(d.tokens),
+		nextTokenID: d.nextTokenID,
+		externalPRs: maps.Clone(d.externalPRs),
+
+		subscriptions:      maps.Clone(d.subscriptions),
+		nextSubscriptionID: d.nextSubscriptionID,
+		deliveries:         maps.Clone(d.deliveries),
+		nextDeliveryID:     d.nextDeliveryID,
+
+		outbox:       maps.Clone(d.outbox),
+		nextOutboxID: d.nextOutboxID,
+
+		audit: slices.Clone(d.audit),
+	}
+}
+
+// userTeams возвращает команды пользователя: основная первой, остальные по id.
+func (d *memoryData) userTeams(userID int) []int {
+	var members []memoryMember
+	for _, m := range d.members {
+		if m.userID == userID {
+			members = append(members, m)
+		}
+	}
+
+	slices.SortFunc(members, func(a, b memoryMember) int {
+		if a.isPrimary != b.isPrimary {
+			if a.isPrimary {
+				return -1
+			}
+			return 1
+		}
+		return a.teamID - b.teamID
+	})
+
+	teamIDs := make([]int, 0, len(members))
+	for _, m := range members {
+		teamIDs = append(teamIDs, m.teamID)
+	}
+
+	return teamIDs
+}
+
+type memoryUnitOfWork struct {
+	db *memoryDB
+}
+
+// Do выполняет fn под блокировкой хранилища; при ошибке или панике изменения откатываются.
+// Вложенный вызов переиспользует уже открытую транзакцию.
+func (u *memoryUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx, ok := ctx.Value(memoryTxKey{}).(*memoryDB); ok && tx == u.db {
+		return fn(ctx)
+	}
+
+	u.db.mu.Lock()
+	defer u.db.mu.Unlock()
+
+	snapshot := u.db.data.clone()
+	committed := false
+	defer func() {
+		if !committed {
+			u.db.data = snapshot
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, memoryTxKey{}, u.db)); err != nil {
+		return err
+	}
+
+	committed = true
+	return nil
+}
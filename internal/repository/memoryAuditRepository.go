@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"PR-appointer/internal/entity"
+)
+
+type memoryAuditRepository struct {
+	db *memoryDB
+}
+
+func (r *memoryAuditRepository) Add(ctx context.Context, entry *entity.AuditEntry) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	stored := *entry
+	stored.ID = len(d.audit) + 1
+	stored.Before = slices.Clone(entry.Before)
+	stored.After = slices.Clone(entry.After)
+	stored.CreatedAt = time.Now()
+	d.audit = append(d.audit, stored)
+
+	return nil
+}
+
+func (r *memoryAuditRepository) List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error) {
+	defer r.db.lock(ctx)()
+
+	entries := []entity.AuditEntry{}
+	for _, entry := range slices.Backward(r.db.data.audit) {
+		if len(entries) == filter.Limit {
+			break
+		}
+		if filter.PullRequestID != 0 && entry.PullRequestID != filter.PullRequestID {
+			continue
+		}
+		if filter.UserID != 0 && entry.UserID != filter.UserID {
+			continue
+		}
+		if filter.TeamID != 0 && entry.TeamID != filter.TeamID {
+			continue
+		}
+		if filter.From != nil && entry.CreatedAt.Before(*filter.From) {
+			continue
+		}
+		if filter.To != nil && !entry.CreatedAt.Before(*filter.To) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
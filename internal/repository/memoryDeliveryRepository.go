@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"PR-appointer/internal/entity"
+)
+
+type memoryDeliveryRepository struct {
+	db *memoryDB
+}
+
+// Enqueue ставит событие в очередь подписки; повторная постановка того же события ничего не меняет.
+func (r *memoryDeliveryRepository) Enqueue(ctx context.Context, subscriptionID int, eventID, eventType string, payload []byte) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	if _, ok := d.subscriptions[subscriptionID]; !ok {
+		return fmt.Errorf("failed to enqueue delivery: subscription %d not found", subscriptionID)
+	}
+
+	for _, delivery := range d.deliveries {
+		if delivery.SubscriptionID == subscriptionID && delivery.EventID == eventID {
+			return nil
+		}
+	}
+
+	now := time.Now()
+	d.nextDeliveryID++
+	d.deliveries[d.nextDeliveryID] = entity.WebhookDelivery{
+		ID:             d.nextDeliveryID,
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		EventType:      eventType,
+		Payload:        slices.Clone(payload),
+		Status:         entity.DeliveryPending,
+		NextAttemptAt:  &now,
+		CreatedAt:      now,
+	}
+
+	return nil
+}
+
+func (r *memoryDeliveryRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookDelivery, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	now := time.Now()
+	var due []entity.WebhookDelivery
+	for _, delivery := range d.deliveries {
+		if delivery.Status == entity.DeliveryPending && delivery.NextAttemptAt != nil && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	slices.SortFunc(due, func(a, b entity.WebhookDelivery) int {
+		if c := a.NextAttemptAt.Compare(*b.NextAttemptAt); c != 0 {
+			return c
+		}
+		return a.ID - b.ID
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	leaseUntil := now.Add(lease)
+	for i := range due {
+		due[i].NextAttemptAt = &leaseUntil
+		d.deliveries[due[i].ID] = due[i]
+	}
+
+	return due, nil
+}
+
+func (r *memoryDeliveryRepository) RecordAttempt(ctx context.Context, deliveryID int, attempt entity.DeliveryAttempt) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	delivery, ok := d.deliveries[deliveryID]
+	if !ok {
+		return nil
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.Status = deliveryStatus(attempt)
+	delivery.ResponseStatus = nil
+	if attempt.ResponseStatus != 0 {
+		delivery.ResponseStatus = &attempt.ResponseStatus
+	}
+	delivery.LastError = nil
+	if attempt.Error != "" {
+		delivery.LastError = &attempt.Error
+	}
+	delivery.NextAttemptAt = nil
+	if delivery.Status == entity.DeliveryPending {
+		next := now.Add(attempt.RetryIn)
+		delivery.NextAttemptAt = &next
+	}
+	delivery.DeliveredAt = nil
+	if delivery.Status == entity.DeliverySucceeded {
+		delivery.DeliveredAt = &now
+	}
+	d.deliveries[deliveryID] = delivery
+
+	return nil
+}
+
+func (r *memoryDeliveryRepository) List(ctx context.Context, filter entity.DeliveryFilter) ([]entity.WebhookDelivery, error) {
+	defer r.db.lock(ctx)()
+
+	deliveries := []entity.WebhookDelivery{}
+	for _, delivery := range r.db.data.deliveries {
+		if filter.SubscriptionID != 0 && delivery.SubscriptionID != filter.SubscriptionID {
+			continue
+		}
+		if filter.Status != "" && delivery.Status != filter.Status {
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	slices.SortFunc(deliveries, func(a, b entity.WebhookDelivery) int {
+		return b.ID - a.ID
+	})
+	if len(deliveries) > filter.Limit {
+		deliveries = deliveries[:filter.Limit]
+	}
+
+	return deliveries, nil
+}
+
+func (r *memoryDeliveryRepository) Redeliver(ctx context.Context, deliveryID int) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	delivery, ok := d.deliveries[deliveryID]
+	if !ok {
+		return errors.New("delivery not found")
+	}
+
+	now := time.Now()
+	delivery.Status = entity.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = &now
+	delivery.LastError = nil
+	d.deliveries[deliveryID] = delivery
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type memoryExternalPRRepository struct {
+	db *memoryDB
+}
+
+func (r *memoryExternalPRRepository) GetPRID(ctx context.Context, provider, repository string, number int) (int, error) {
+	defer r.db.lock(ctx)()
+
+	prID, ok := r.db.data.externalPRs[memoryExternalKey{provider, repository, number}]
+	if !ok {
+		return 0, errors.New("external PR not found")
+	}
+
+	return prID, nil
+}
+
+func (r *memoryExternalPRRepository) Link(ctx context.Context, provider, repository string, number, prID int) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	if _, ok := d.prs[prID]; !ok {
+		return fmt.Errorf("failed to link external PR: PR %d not found", prID)
+	}
+
+	key := memoryExternalKey{provider, repository, number}
+	if _, ok := d.externalPRs[key]; ok {
+		return errors.New("external PR already linked")
+	}
+	for _, linked := range d.externalPRs {
+		if linked == prID {
+			return errors.New("external PR already linked")
+		}
+	}
+
+	d.externalPRs[key] = prID
+	return nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"PR-appointer/internal/entity"
+)
+
+// memoryOutboxEvent - событие outbox вместе с состоянием обработки
+type memoryOutboxEvent struct {
+	entity.OutboxEvent
+	nextAttemptAt time.Time
+	processed     bool
+	failed        bool
+	lastError     string
+}
+
+type memoryOutboxRepository struct {
+	db *memoryDB
+}
+
+func (r *memoryOutboxRepository) Add(ctx context.Context, eventID, eventType string, payload []byte) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	for _, event := range d.outbox {
+		if event.EventID == eventID {
+			return fmt.Errorf("failed to add outbox event: event %s already exists", eventID)
+		}
+	}
+
+	now := time.Now()
+	d.nextOutboxID++
+	d.outbox[d.nextOutboxID] = memoryOutboxEvent{
+		OutboxEvent: entity.OutboxEvent{
+			ID:        d.nextOutboxID,
+			EventID:   eventID,
+			EventType: eventType,
+			Payload:   slices.Clone(payload),
+			CreatedAt: now,
+		},
+		nextAttemptAt: now,
+	}
+
+	return nil
+}
+
+func (r *memoryOutboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxEvent, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	now := time.Now()
+	var ids []int
+	for id, event := range d.outbox {
+		if !event.processed && !event.failed && !event.nextAttemptAt.After(now) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	events := []entity.OutboxEvent{}
+	for _, id := range ids {
+		event := d.outbox[id]
+		event.nextAttemptAt = now.Add(lease)
+		d.outbox[id] = event
+		events = append(events, event.OutboxEvent)
+	}
+
+	return events, nil
+}
+
+func (r *memoryOutboxRepository) MarkProcessed(ctx context.Context, id int) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	event, ok := d.outbox[id]
+	if !ok {
+		return nil
+	}
+	event.Attempts++
+	event.processed = true
+	event.lastError = ""
+	d.outbox[id] = event
+
+	return nil
+}
+
+func (r *memoryOutboxRepository) Reschedule(ctx context.Context, id int, retryIn time.Duration, lastError string) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	event, ok := d.outbox[id]
+	if !ok {
+		return nil
+	}
+	event.Attempts++
+	event.nextAttemptAt = time.Now().Add(retryIn)
+	event.lastError = lastError
+	d.outbox[id] = event
+
+	return nil
+}
+
+func (r *memoryOutboxRepository) MarkFailed(ctx context.Context, id int, lastError string) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	event, ok := d.outbox[id]
+	if !ok {
+		return nil
+	}
+	event.Attempts++
+	event.failed = true
+	event.lastError = lastError
+	d.outbox[id] = event
+
+	return nil
+}
@@ -0,0 +1,417 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"PR-appointer/internal/entity"
+)
+
+type memoryPRRepository struct {
+	db *memoryDB
+}
+
+func (r *memoryPRRepository) Create(ctx context.Context, id int, title string, authorID, teamID int, status string, degraded bool) (*entity.PullRequest, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	// id 0 - PR без своего id (например, из вебхука): выдаем следующий за максимальным
+	if id == 0 {
+		for prID := range d.prs {
+			id = max(id, prID)
+		}
+		id++
+	}
+	if _, ok := d.prs[id]; ok {
+		return nil, errors.New("PR already exists")
+	}
+	if _, ok := d.users[authorID]; !ok {
+		return nil, fmt.Errorf("failed to create PR: author %d not found", authorID)
+	}
+
+	now := time.Now()
+	pr := entity.PullRequest{
+		ID:        id,
+		Title:     title,
+		AuthorID:  authorID,
+		TeamID:    &teamID,
+		Status:    status,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Degraded:  degraded,
+	}
+	d.prs[id] = pr
+
+	return &pr, nil
+}
+
+func (r *memoryPRRepository) GetByID(ctx context.Context, prID int) (*entity.PullRequest, error) {
+	defer r.db.lock(ctx)()
+
+	pr, ok := r.db.data.prs[prID]
+	if !ok {
+		return nil, errors.New("PR not found")
+	}
+
+	return &pr, nil
+}
+
+// GetByIDForUpdate совпадает с GetByID: транзакция в памяти и так держит
+// блокировку всего хранилища.
+func (r *memoryPRRepository) GetByIDForUpdate(ctx context.Context, prID int) (*entity.PullRequest, error) {
+	return r.GetByID(ctx, prID)
+}
+
+func (r *memoryPRRepository) UpdateStatus(ctx context.Context, prID int, status string) (*entity.PullRequest, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	pr, ok := d.prs[prID]
+	if !ok {
+		return nil, errors.New("PR not found")
+	}
+
+	now := time.Now()
+	pr.Status = status
+	pr.UpdatedAt = now
+	if status == entity.StatusMerged {
+		pr.MergedAt = sql.NullTime{Time: now, Valid: true}
+	}
+	if status == entity.StatusClosed {
+		pr.ClosedAt = sql.NullTime{Time: now, Valid: true}
+	} else {
+		pr.ClosedAt = sql.NullTime{}
+	}
+	d.prs[prID] = pr
+
+	return &pr, nil
+}
+
+func (r *memoryPRRepository) SetDegraded(ctx context.Context, prID int, degraded bool) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	if pr, ok := d.prs[prID]; ok {
+		pr.Degraded = degraded
+		pr.UpdatedAt = time.Now()
+		d.prs[prID] = pr
+	}
+
+	return nil
+}
+
+func (r *memoryPRRepository) AddReviewer(ctx context.Context, prID int, reviewerID, teamID int, crossTeam bool) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	if _, ok := d.prs[prID]; !ok {
+		return fmt.Errorf("failed to add reviewer: PR %d not found", prID)
+	}
+	if _, ok := d.users[reviewerID]; !ok {
+		return fmt.Errorf("failed to add reviewer: user %d not found", reviewerID)
+	}
+	if d.reviewerIndex(prID, reviewerID) >= 0 {
+		return errors.New("reviewer is already assigned to this PR")
+	}
+
+	d.reviewers = append(d.reviewers, memoryReviewer{
+		prID:       prID,
+		reviewerID: reviewerID,
+		teamID:     teamID,
+		crossTeam:  crossTeam,
+		assignedAt: time.Now(),
+	})
+
+	return nil
+}
+
+func (r *memoryPRRepository) RemoveReviewer(ctx context.Context, prID int, reviewerID int) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	d.reviewers = slices.DeleteFunc(d.reviewers, func(rv memoryReviewer) bool {
+		return rv.prID == prID && rv.reviewerID == reviewerID
+	})
+
+	return nil
+}
+
+func (r *memoryPRRepository) RemoveAllReviewers(ctx context.Context, prID int) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	d.reviewers = slices.DeleteFunc(d.reviewers, func(rv memoryReviewer) bool {
+		return rv.prID == prID
+	})
+
+	return nil
+}
+
+func (r *memoryPRRepository) GetReviewers(ctx context.Context, prID int) ([]entity.ReviewerResponse, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	var reviewers []entity.ReviewerResponse
+	for _, rv := range d.reviewers {
+		if rv.prID != prID {
+			continue
+		}
+
+		user := d.users[rv.reviewerID]
+		reviewer := entity.ReviewerResponse{
+			UserID:     user.ID,
+			Username:   user.Username,
+			IsActive:   user.IsActive,
+			TeamID:     rv.teamID,
+			CrossTeam:  rv.crossTeam,
+			Verdict:    rv.verdict,
+			ReviewedAt: rv.reviewedAt,
+			AssignedAt: &rv.assignedAt,
+		}
+
+		// Для старых назначений без команды берем основную команду ревьювера
+		if reviewer.TeamID == 0 {
+			if teamIDs := d.userTeams(user.ID); len(teamIDs) > 0 {
+				reviewer.TeamID = teamIDs[0]
+			}
+		}
+		if team, ok := d.teams[reviewer.TeamID]; ok {
+			reviewer.TeamName = team.Name
+		} else {
+			reviewer.TeamID = 0
+		}
+
+		reviewers = append(reviewers, reviewer)
+	}
+
+	return reviewers, nil
+}
+
+func (r *memoryPRRepository) IsReviewerAssigned(ctx context.Context, prID int, reviewerID int) (bool, error) {
+	defer r.db.lock(ctx)()
+
+	return r.db.data.reviewerIndex(prID, reviewerID) >= 0, nil
+}
+
+func (r *memoryPRRepository) GetPRsByReviewer(ctx context.Context, reviewerID int) ([]entity.PRSummary, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	type reviewedPR struct {
+		summary   entity.PRSummary
+		createdAt time.Time
+	}
+
+	var reviewed []reviewedPR
+	for _, rv := range d.reviewers {
+		if rv.reviewerID != reviewerID {
+			continue
+		}
+		pr := d.prs[rv.prID]
+		reviewed = append(reviewed, reviewedPR{
+			summary: entity.PRSummary{
+				PullRequestID:   pr.ID,
+				PullRequestName: pr.Title,
+				AuthorID:        pr.AuthorID,
+				Status:          pr.Status,
+				Verdict:         rv.verdict,
+				ReviewedAt:      rv.reviewedAt,
+			},
+			createdAt: pr.CreatedAt,
+		})
+	}
+
+	slices.SortStableFunc(reviewed, func(a, b reviewedPR) int {
+		return b.createdAt.Compare(a.createdAt)
+	})
+
+	var prs []entity.PRSummary
+	for _, item := range reviewed {
+		prs = append(prs, item.summary)
+	}
+
+	return prs, nil
+}
+
+func (r *memoryPRRepository) SetVerdict(ctx context.Context, prID, reviewerID int, verdict string) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	i := d.reviewerIndex(prID, reviewerID)
+	if i < 0 {
+		return errors.New("reviewer is not assigned to this PR")
+	}
+
+	now := time.Now()
+	d.reviewers[i].verdict = verdict
+	d.reviewers[i].reviewedAt = &now
+
+	return nil
+}
+
+func (r *memoryPRRepository) CountApprovals(ctx context.Context, prID int) (int, error) {
+	defer r.db.lock(ctx)()
+
+	count := 0
+	for _, rv := range r.db.data.reviewers {
+		if rv.prID == prID && rv.verdict == entity.VerdictApproved {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *memoryPRRepository) GetLastAssignedAt(ctx context.Context, reviewerIDs []int) (map[int]time.Time, error) {
+	defer r.db.lock(ctx)()
+
+	lastAssigned := make(map[int]time.Time)
+	for _, rv := range r.db.data.reviewers {
+		if !slices.Contains(reviewerIDs, rv.reviewerID) {
+			continue
+		}
+		if rv.assignedAt.After(lastAssigned[rv.reviewerID]) {
+			lastAssigned[rv.reviewerID] = rv.assignedAt
+		}
+	}
+
+	return lastAssigned, nil
+}
+
+func (r *memoryPRRepository) GetOpenReviewCounts(ctx context.Context, reviewerIDs []int) (map[int]int, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	counts := make(map[int]int)
+	for _, rv := range d.reviewers {
+		if slices.Contains(reviewerIDs, rv.reviewerID) && d.prs[rv.prID].Status == entity.StatusOpen {
+			counts[rv.reviewerID]++
+		}
+	}
+
+	return counts, nil
+}
+
+func (r *memoryPRRepository) GetOpenAssignments(ctx context.Context, reviewerIDs []int) ([]entity.ReviewAssignment, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	var assignments []entity.ReviewAssignment
+	for _, rv := range d.reviewers {
+		if slices.Contains(reviewerIDs, rv.reviewerID) && d.prs[rv.prID].Status == entity.StatusOpen {
+			assignments = append(assignments, entity.ReviewAssignment{
+				PullRequestID: rv.prID,
+				ReviewerID:    rv.reviewerID,
+			})
+		}
+	}
+
+	slices.SortFunc(assignments, func(a, b entity.ReviewAssignment) int {
+		if a.PullRequestID != b.PullRequestID {
+			return a.PullRequestID - b.PullRequestID
+		}
+		return a.ReviewerID - b.ReviewerID
+	})
+
+	return assignments, nil
+}
+
+func (d *memoryData) reviewerIndex(prID, reviewerID int) int {
+	return slices.IndexFunc(d.reviewers, func(rv memoryReviewer) bool {
+		return rv.prID == prID && rv.reviewerID == reviewerID
+	})
+}
+
+func (r *memoryPRRepository) List(ctx context.Context, filter entity.PRListFilter) ([]entity.PRListItem, error) {
+	if _, _, _, err := prListOrder(filter); err != nil {
+		return nil, err
+	}
+
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	// Порядок как в ORDER BY <поле>, id; для убывания сравнение обращается
+	order := func(a, b entity.PullRequest) int {
+		var c int
+		switch filter.Sort {
+		case entity.PRSortCreatedAt:
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		case entity.PRSortUpdatedAt:
+			c = a.UpdatedAt.Compare(b.UpdatedAt)
+		case entity.PRSortTitle:
+			c = strings.Compare(a.Title, b.Title)
+		}
+		if c == 0 {
+			c = cmp.Compare(a.ID, b.ID)
+		}
+		if filter.Desc {
+			return -c
+		}
+		return c
+	}
+
+	// Позиция курсора сравнивается как PR с его значениями
+	var after *entity.PullRequest
+	if filter.After != nil {
+		after = &entity.PullRequest{
+			ID:        filter.After.ID,
+			CreatedAt: filter.After.Time,
+			UpdatedAt: filter.After.Time,
+			Title:     filter.After.Title,
+		}
+	}
+
+	inRange := func(t time.Time, from, to *time.Time) bool {
+		return (from == nil || !t.Before(*from)) && (to == nil || t.Before(*to))
+	}
+
+	var matched []entity.PullRequest
+	for _, pr := range d.prs {
+		switch {
+		case filter.Status != "" && pr.Status != filter.Status,
+			filter.AuthorID != 0 && pr.AuthorID != filter.AuthorID,
+			filter.ReviewerID != 0 && d.reviewerIndex(pr.ID, filter.ReviewerID) < 0,
+			filter.TeamID != 0 && (pr.TeamID == nil || *pr.TeamID != filter.TeamID),
+			filter.Title != "" && !strings.Contains(strings.ToLower(pr.Title), strings.ToLower(filter.Title)),
+			!inRange(pr.CreatedAt, filter.CreatedFrom, filter.CreatedTo),
+			(filter.MergedFrom != nil || filter.MergedTo != nil) &&
+				(!pr.MergedAt.Valid || !inRange(pr.MergedAt.Time, filter.MergedFrom, filter.MergedTo)),
+			after != nil && order(pr, *after) <= 0:
+			continue
+		}
+		matched = append(matched, pr)
+	}
+
+	slices.SortFunc(matched, order)
+
+	prs := []entity.PRListItem{}
+	for _, pr := range matched[:min(filter.Limit, len(matched))] {
+		item := entity.PRListItem{
+			PullRequestID:   pr.ID,
+			PullRequestName: pr.Title,
+			AuthorID:        pr.AuthorID,
+			Status:          pr.Status,
+			Degraded:        pr.Degraded,
+			CreatedAt:       pr.CreatedAt,
+			UpdatedAt:       pr.UpdatedAt,
+		}
+		if pr.TeamID != nil {
+			item.TeamName = d.teams[*pr.TeamID].Name
+		}
+		if pr.MergedAt.Valid {
+			item.MergedAt = &pr.MergedAt.Time
+		}
+		if pr.ClosedAt.Valid {
+			item.ClosedAt = &pr.ClosedAt.Time
+		}
+		prs = append(prs, item)
+	}
+
+	return prs, nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"PR-appointer/internal/entity"
+)
+
+type memorySubscriptionRepository struct {
+	db *memoryDB
+}
+
+func (r *memorySubscriptionRepository) Create(ctx context.Context, url, secret string, events []string) (*entity.WebhookSubscription, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	d.nextSubscriptionID++
+	subscription := entity.WebhookSubscription{
+		ID:        d.nextSubscriptionID,
+		URL:       url,
+		Secret:    secret,
+		Events:    slices.Clone(events),
+		CreatedAt: time.Now(),
+	}
+	d.subscriptions[subscription.ID] = subscription
+
+	return &subscription, nil
+}
+
+func (r *memorySubscriptionRepository) GetByID(ctx context.Context, subscriptionID int) (*entity.WebhookSubscription, error) {
+	defer r.db.lock(ctx)()
+
+	subscription, ok := r.db.data.subscriptions[subscriptionID]
+	if !ok {
+		return nil, errors.New("subscription not found")
+	}
+
+	return &subscription, nil
+}
+
+func (r *memorySubscriptionRepository) List(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	defer r.db.lock(ctx)()
+
+	subscriptions := []entity.WebhookSubscription{}
+	for _, subscription := range r.db.data.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	slices.SortFunc(subscriptions, func(a, b entity.WebhookSubscription) int {
+		return a.ID - b.ID
+	})
+
+	return subscriptions, nil
+}
+
+func (r *memorySubscriptionRepository) Delete(ctx context.Context, subscriptionID int) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	if _, ok := d.subscriptions[subscriptionID]; !ok {
+		return errors.New("subscription not found")
+	}
+
+	// Как ON DELETE CASCADE в базе
+	delete(d.subscriptions, subscriptionID)
+	for id, delivery := range d.deliveries {
+		if delivery.SubscriptionID == subscriptionID {
+			delete(d.deliveries, id)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"PR-appointer/internal/entity"
+)
+
+type memoryTeamRepository struct {
+	db *memoryDB
+}
+
+func (r *memoryTeamRepository) Create(ctx context.Context, name string) (*entity.Team, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	for _, t := range d.teams {
+		if t.Name == name {
+			return nil, fmt.Errorf("failed to create team: team %q already exists", name)
+		}
+	}
+
+	d.nextTeamID++
+	now := time.Now()
+	team := entity.Team{
+		ID:        d.nextTeamID,
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	d.teams[team.ID] = team
+
+	return &team, nil
+}
+
+func (r *memoryTeamRepository) GetByName(ctx context.Context, name string) (*entity.Team, error) {
+	defer r.db.lock(ctx)()
+
+	for _, t := range r.db.data.teams {
+		if t.Name == name {
+			return &t, nil
+		}
+	}
+
+	return nil, errors.New("team not found")
+}
+
+func (r *memoryTeamRepository) GetByID(ctx context.Context, teamID int) (*entity.Team, error) {
+	defer r.db.lock(ctx)()
+
+	team, ok := r.db.data.teams[teamID]
+	if !ok {
+		return nil, errors.New("team not found")
+	}
+
+	return &team, nil
+}
+
+func (r *memoryTeamRepository) AddMember(ctx context.Context, teamID, userID, reviewWeight int) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	if _, ok := d.teams[teamID]; !ok {
+		return fmt.Errorf("failed to add team member: team %d not found", teamID)
+	}
+	if _, ok := d.users[userID]; !ok {
+		return fmt.Errorf("failed to add team member: user %d not found", userID)
+	}
+
+	for i, m := range d.members {
+		if m.teamID == teamID && m.userID == userID {
+			d.members[i].reviewWeight = reviewWeight
+			return nil
+		}
+	}
+
+	d.members = append(d.members, memoryMember{
+		teamID:       teamID,
+		userID:       userID,
+		reviewWeight: reviewWeight,
+	})
+
+	return nil
+}
+
+func (r *memoryTeamRepository) GetMembers(ctx context.Context, teamID int) ([]entity.UserResponse, error) {
+	defer r.db.lock(ctx)()
+
+	return r.db.data.teamMembers(teamID, false, nil), nil
+}
+
+func (r *memoryTeamRepository) GetActiveMembers(ctx context.Context, teamID int, excludeUserID *int) ([]entity.UserResponse, error) {
+	defer r.db.lock(ctx)()
+
+	return r.db.data.teamMembers(teamID, true, excludeUserID), nil
+}
+
+func (r *memoryTeamRepository) SetPrimaryTeam(ctx context.Context, userID, teamID int) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	for i, m := range d.members {
+		if m.userID == userID {
+			d.members[i].isPrimary = m.teamID == teamID
+		}
+	}
+
+	return nil
+}
+
+func (r *memoryTeamRepository) GetMemberWeights(ctx context.Context, teamID int) (map[int]int, error) {
+	defer r.db.lock(ctx)()
+
+	weights := make(map[int]int)
+	for _, m := range r.db.data.members {
+		if m.teamID == teamID {
+			weights[m.userID] = m.reviewWeight
+		}
+	}
+
+	return weights, nil
+}
+
+func (r *memoryTeamRepository) GetSettings(ctx context.Context, teamID int) (*entity.TeamSettings, error) {
+	defer r.db.lock(ctx)()
+
+	settings, ok := r.db.data.settings[teamID]
+	if !ok {
+		// Настройки не заданы - используем значения по умолчанию
+		return &entity.TeamSettings{
+			TeamID:           teamID,
+			ReviewerStrategy: entity.StrategyRandom,
+			ReviewersPerPR:   entity.DefaultReviewersPerPR,
+		}, nil
+	}
+
+	return &settings, nil
+}
+
+func (r *memoryTeamRepository) UpsertSettings(ctx context.Context, settings *entity.TeamSettings) (*entity.TeamSettings, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	if _, ok := d.teams[settings.TeamID]; !ok {
+		return nil, fmt.Errorf("failed to save team settings: team %d not found", settings.TeamID)
+	}
+
+	saved := *settings
+	saved.UpdatedAt = time.Now()
+	d.settings[saved.TeamID] = saved
+
+	return &saved, nil
+}
+
+func (r *memoryTeamRepository) GetFallbackTeams(ctx context.Context, teamID int) ([]entity.Team, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	var teams []entity.Team
+	for _, id := range d.fallbacks[teamID] {
+		if team, ok := d.teams[id]; ok {
+			teams = append(teams, team)
+		}
+	}
+
+	return teams, nil
+}
+
+func (r *memoryTeamRepository) SetFallbackTeams(ctx context.Context, teamID int, fallbackTeamIDs []int) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	for _, id := range fallbackTeamIDs {
+		if id == teamID {
+			return errors.New("failed to add fallback team: team cannot be its own fallback")
+		}
+		if _, ok := d.teams[id]; !ok {
+			return fmt.Errorf("failed to add fallback team: team %d not found", id)
+		}
+	}
+
+	d.fallbacks[teamID] = slices.Clone(fallbackTeamIDs)
+
+	return nil
+}
+
+// teamMembers возвращает участников команды по возрастанию id.
+func (d *memoryData) teamMembers(teamID int, activeOnly bool, excludeUserID *int) []entity.UserResponse {
+	var members []entity.UserResponse
+	for _, m := range d.members {
+		if m.teamID != teamID {
+			continue
+		}
+		user := d.users[m.userID]
+		if activeOnly && !user.IsActive {
+			continue
+		}
+		if excludeUserID != nil && user.ID == *excludeUserID {
+			continue
+		}
+		members = append(members, entity.UserResponse{
+			UserID:   user.ID,
+			Username: user.Username,
+			TeamName: d.teams[teamID].Name,
+			IsActive: user.IsActive,
+		})
+	}
+
+	slices.SortFunc(members, func(a, b entity.UserResponse) int {
+		return a.UserID - b.UserID
+	})
+
+	return members
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"PR-appointer/internal/entity"
+)
+
+type memoryTokenRepository struct {
+	db *memoryDB
+}
+
+func (r *memoryTokenRepository) Create(ctx context.Context, name, tokenHash, role string, userID *int) (*entity.APIToken, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	for _, t := range d.tokens {
+		if t.TokenHash == tokenHash {
+			return nil, errors.New("failed to create token: token hash already exists")
+		}
+	}
+	if userID != nil {
+		if _, ok := d.users[*userID]; !ok {
+			return nil, fmt.Errorf("failed to create token: user %d does not exist", *userID)
+		}
+	}
+
+	d.nextTokenID++
+	token := entity.APIToken{
+		ID:        d.nextTokenID,
+		Name:      name,
+		TokenHash: tokenHash,
+		Role:      role,
+		UserID:    userID,
+		CreatedAt: time.Now(),
+	}
+	d.tokens[token.ID] = token
+
+	return &token, nil
+}
+
+func (r *memoryTokenRepository) GetPrincipal(ctx context.Context, tokenHash string) (*entity.Principal, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	for _, t := range d.tokens {
+		if t.TokenHash != tokenHash || t.RevokedAt != nil {
+			continue
+		}
+
+		principal := entity.Principal{TokenID: t.ID, Role: t.Role}
+		if t.UserID != nil {
+			user, ok := d.users[*t.UserID]
+			if !ok || !user.IsActive {
+				break
+			}
+			principal.UserID = user.ID
+			principal.Username = user.Username
+		}
+
+		return &principal, nil
+	}
+
+	return nil, errors.New("token not found")
+}
+
+func (r *memoryTokenRepository) Revoke(ctx context.Context, tokenID int) error {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	token, ok := d.tokens[tokenID]
+	if !ok || token.RevokedAt != nil {
+		return errors.New("token not found")
+	}
+
+	now := time.Now()
+	token.RevokedAt = &now
+	d.tokens[tokenID] = token
+
+	return nil
+}
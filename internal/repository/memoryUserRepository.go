@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"PR-appointer/internal/entity"
+)
+
+type memoryUserRepository struct {
+	db *memoryDB
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, username string, isActive bool) (*entity.User, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	for _, u := range d.users {
+		if u.Username == username {
+			return nil, fmt.Errorf("failed to create user: username %q already exists", username)
+		}
+	}
+
+	user := d.insertUser(username, isActive)
+	return &user, nil
+}
+
+func (r *memoryUserRepository) GetByID(ctx context.Context, userID int) (*entity.UserResponse, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	// Как и в Postgres, пользователь без команды не находится
+	user, ok := d.userResponse(userID)
+	if !ok || len(d.userTeams(userID)) == 0 {
+		return nil, errors.New("user not found")
+	}
+
+	return &user, nil
+}
+
+func (r *memoryUserRepository) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
+	defer r.db.lock(ctx)()
+
+	for _, u := range r.db.data.users {
+		if u.Username == username {
+			return &u, nil
+		}
+	}
+
+	return nil, nil // User doesn't exist
+}
+
+func (r *memoryUserRepository) UpdateStatus(ctx context.Context, userID int, isActive bool) (*entity.UserResponse, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	user, ok := d.users[userID]
+	if ok {
+		user.IsActive = isActive
+		user.UpdatedAt = time.Now()
+		d.users[userID] = user
+	}
+
+	response, ok := d.userResponse(userID)
+	if !ok || len(d.userTeams(userID)) == 0 {
+		return nil, errors.New("user not found")
+	}
+
+	return &response, nil
+}
+
+func (r *memoryUserRepository) Upsert(ctx context.Context, username string, isActive bool) (*entity.UserResponse, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	userID := 0
+	for id, u := range d.users {
+		if u.Username == username {
+			u.IsActive = isActive
+			u.UpdatedAt = time.Now()
+			d.users[id] = u
+			userID = id
+			break
+		}
+	}
+	if userID == 0 {
+		userID = d.insertUser(username, isActive).ID
+	}
+
+	response, _ := d.userResponse(userID)
+	return &response, nil
+}
+
+func (r *memoryUserRepository) GetTeamsByUserID(ctx context.Context, userID int) ([]int, error) {
+	defer r.db.lock(ctx)()
+
+	return r.db.data.userTeams(userID), nil
+}
+
+func (r *memoryUserRepository) SetActiveMany(ctx context.Context, userIDs []int, isActive bool) ([]entity.User, error) {
+	defer r.db.lock(ctx)()
+	d := r.db.data
+
+	ids := slices.Clone(userIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	var users []entity.User
+	for _, id := range ids {
+		user, ok := d.users[id]
+		if !ok {
+			continue
+		}
+		user.IsActive = isActive
+		user.UpdatedAt = time.Now()
+		d.users[id] = user
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+func (d *memoryData) insertUser(username string, isActive bool) entity.User {
+	d.nextUserID++
+	now := time.Now()
+	user := entity.User{
+		ID:        d.nextUserID,
+		Username:  username,
+		IsActive:  isActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	d.users[user.ID] = user
+
+	return user
+}
+
+// userResponse собирает пользователя с именем его основной команды
+// (пустым, если пользователь не состоит в командах).
+func (d *memoryData) userResponse(userID int) (entity.UserResponse, bool) {
+	user, ok := d.users[userID]
+	if !ok {
+		return entity.UserResponse{}, false
+	}
+
+	response := entity.UserResponse{
+		UserID:   user.ID,
+		Username: user.Username,
+		IsActive: user.IsActive,
+	}
+	if teamIDs := d.userTeams(userID); len(teamIDs) > 0 {
+		response.TeamName = d.teams[teamIDs[0]].Name
+	}
+
+	return response, true
+}
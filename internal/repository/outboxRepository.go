@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"PR-appointer/internal/entity"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgOutboxRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPgOutboxRepository(db *pgxpool.Pool) *PgOutboxRepository {
+	return &PgOutboxRepository{db: db}
+}
+
+func (r *PgOutboxRepository) conn(ctx context.Context) DBTX {
+	return executor(ctx, r.db)
+}
+
+func (r *PgOutboxRepository) Add(ctx context.Context, eventID, eventType string, payload []byte) error {
+	query := `
+		INSERT INTO outbox_events (event_id, event_type, payload)
+		VALUES ($1, $2, $3)
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, eventID, eventType, payload); err != nil {
+		return fmt.Errorf("failed to add outbox event: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDue забирает необработанные события по порядку записи и откладывает их на lease,
+// чтобы после падения процесса событие обработалось снова.
+func (r *PgOutboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxEvent, error) {
+	query := `
+		UPDATE outbox_events
+		SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE processed_at IS NULL AND failed_at IS NULL AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_id, event_type, payload, attempts, created_at
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []entity.OutboxEvent{}
+	for rows.Next() {
+		var e entity.OutboxEvent
+		if err := rows.Scan(&e.ID, &e.EventID, &e.EventType, &e.Payload, &e.Attempts, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *PgOutboxRepository) MarkProcessed(ctx context.Context, id int) error {
+	query := `
+		UPDATE outbox_events
+		SET processed_at = CURRENT_TIMESTAMP, attempts = attempts + 1, last_error = NULL
+		WHERE id = $1
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to mark outbox event processed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PgOutboxRepository) Reschedule(ctx context.Context, id int, retryIn time.Duration, lastError string) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1,
+			next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2),
+			last_error = $3
+		WHERE id = $1
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, id, retryIn.Seconds(), lastError); err != nil {
+		return fmt.Errorf("failed to reschedule outbox event: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed прекращает повторы события; оно остается в outbox с последней ошибкой.
+func (r *PgOutboxRepository) MarkFailed(ctx context.Context, id int, lastError string) error {
+	query := `
+		UPDATE outbox_events
+		SET failed_at = CURRENT_TIMESTAMP, attempts = attempts + 1, last_error = $2
+		WHERE id = $1
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, id, lastError); err != nil {
+		return fmt.Errorf("failed to mark outbox event failed: %w", err)
+	}
+
+	return nil
+}
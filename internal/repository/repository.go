@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"PR-appointer/internal/entity"
+)
+
+// PRRepository - хранилище PR и назначенных на них ревьюверов.
+type PRRepository interface {
+	// Create с id 0 создает PR с id, который выдает само хранилище
+	Create(ctx context.Context, id int, title string, authorID, teamID int, status string, degraded bool) (*entity.PullRequest, error)
+	GetByID(ctx context.Context, prID int) (*entity.PullRequest, error)
+	GetByIDForUpdate(ctx context.Context, prID int) (*entity.PullRequest, error)
+	UpdateStatus(ctx context.Context, prID int, status string) (*entity.PullRequest, error)
+	SetDegraded(ctx context.Context, prID int, degraded bool) error
+	AddReviewer(ctx context.Context, prID int, reviewerID, teamID int, crossTeam bool) error
+	RemoveReviewer(ctx context.Context, prID int, reviewerID int) error
+	RemoveAllReviewers(ctx context.Context, prID int) error
+	GetReviewers(ctx context.Context, prID int) ([]entity.ReviewerResponse, error)
+	IsReviewerAssigned(ctx context.Context, prID int, reviewerID int) (bool, error)
+	GetPRsByReviewer(ctx context.Context, reviewerID int) ([]entity.PRSummary, error)
+	SetVerdict(ctx context.Context, prID, reviewerID int, verdict string) error
+	CountApprovals(ctx context.Context, prID int) (int, error)
+	GetLastAssignedAt(ctx context.Context, reviewerIDs []int) (map[int]time.Time, error)
+	GetOpenReviewCounts(ctx context.Context, reviewerIDs []int) (map[int]int, error)
+	GetOpenAssignments(ctx context.Context, reviewerIDs []int) ([]entity.ReviewAssignment, error)
+	List(ctx context.Context, filter entity.PRListFilter) ([]entity.PRListItem, error)
+}
+
+// UserRepository - хранилище пользователей.
+type UserRepository interface {
+	Create(ctx context.Context, username string, isActive bool) (*entity.User, error)
+	GetByID(ctx context.Context, userID int) (*entity.UserResponse, error)
+	GetByUsername(ctx context.Context, username string) (*entity.User, error)
+	UpdateStatus(ctx context.Context, userID int, isActive bool) (*entity.UserResponse, error)
+	Upsert(ctx context.Context, username string, isActive bool) (*entity.UserResponse, error)
+	GetTeamsByUserID(ctx context.Context, userID int) ([]int, error)
+	SetActiveMany(ctx context.Context, userIDs []int, isActive bool) ([]entity.User, error)
+}
+
+// TeamRepository - хранилище команд, их участников и настроек.
+type TeamRepository interface {
+	Create(ctx context.Context, name string) (*entity.Team, error)
+	GetByName(ctx context.Context, name string) (*entity.Team, error)
+	GetByID(ctx context.Context, teamID int) (*entity.Team, error)
+	AddMember(ctx context.Context, teamID, userID, reviewWeight int) error
+	GetMembers(ctx context.Context, teamID int) ([]entity.UserResponse, error)
+	GetActiveMembers(ctx context.Context, teamID int, excludeUserID *int) ([]entity.UserResponse, error)
+	SetPrimaryTeam(ctx context.Context, userID, teamID int) error
+	GetMemberWeights(ctx context.Context, teamID int) (map[int]int, error)
+	GetSettings(ctx context.Context, teamID int) (*entity.TeamSettings, error)
+	UpsertSettings(ctx context.Context, settings *entity.TeamSettings) (*entity.TeamSettings, error)
+	GetFallbackTeams(ctx context.Context, teamID int) ([]entity.Team, error)
+	SetFallbackTeams(ctx context.Context, teamID int, fallbackTeamIDs []int) error
+}
+
+// TokenRepository - хранилище API-токенов.
+type TokenRepository interface {
+	Create(ctx context.Context, name, tokenHash, role string, userID *int) (*entity.APIToken, error)
+	GetPrincipal(ctx context.Context, tokenHash string) (*entity.Principal, error)
+	Revoke(ctx context.Context, tokenID int) error
+}
+
+// ExternalPRRepository - связь PR во внешних системах (GitHub, GitLab) с PR сервиса.
+type ExternalPRRepository interface {
+	GetPRID(ctx context.Context, provider, repository string, number int) (int, error)
+	Link(ctx context.Context, provider, repository string, number, prID int) error
+}
+
+// SubscriptionRepository - хранилище подписок на исходящие вебхуки.
+type SubscriptionRepository interface {
+	Create(ctx context.Context, url, secret string, events []string) (*entity.WebhookSubscription, error)
+	GetByID(ctx context.Context, subscriptionID int) (*entity.WebhookSubscription, error)
+	List(ctx context.Context) ([]entity.WebhookSubscription, error)
+	Delete(ctx context.Context, subscriptionID int) error
+}
+
+// DeliveryRepository - очередь и журнал доставок исходящих вебхуков.
+type DeliveryRepository interface {
+	Enqueue(ctx context.Context, subscriptionID int, eventID, eventType string, payload []byte) error
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, deliveryID int, attempt entity.DeliveryAttempt) error
+	List(ctx context.Context, filter entity.DeliveryFilter) ([]entity.WebhookDelivery, error)
+	Redeliver(ctx context.Context, deliveryID int) error
+}
+
+// OutboxRepository - outbox доменных событий. Add вызывается в транзакции изменения,
+// остальные методы - фоновым обработчиком.
+type OutboxRepository interface {
+	Add(ctx context.Context, eventID, eventType string, payload []byte) error
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxEvent, error)
+	MarkProcessed(ctx context.Context, id int) error
+	Reschedule(ctx context.Context, id int, retryIn time.Duration, lastError string) error
+	MarkFailed(ctx context.Context, id int, lastError string) error
+}
+
+// AuditRepository - журнал аудита: записи только добавляются и читаются.
+type AuditRepository interface {
+	Add(ctx context.Context, entry *entity.AuditEntry) error
+	List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error)
+}
+
+// UnitOfWork выполняет вызовы репозиториев одного хранилища атомарно.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Store - репозитории одного хранилища и единица работы над ними.
+type Store struct {
+	PRs           PRRepository
+	Users         UserRepository
+	Teams         TeamRepository
+	Tokens        TokenRepository
+	ExternalPRs   ExternalPRRepository
+	Subscriptions SubscriptionRepository
+	Deliveries    DeliveryRepository
+	Outbox        OutboxRepository
+	Audit         AuditRepository
+	UoW           UnitOfWork
+}
+
+func NewPostgresStore(db *pgxpool.Pool) *Store {
+	return &Store{
+		PRs:           NewPgPRRepository(db),
+		Users:         NewPgUserRepository(db),
+		Teams:         NewPgTeamRepository(db),
+		Tokens:        NewPgTokenRepository(db),
+		ExternalPRs:   NewPgExternalPRRepository(db),
+		Subscriptions: NewPgSubscriptionRepository(db),
+		Deliveries:    NewPgDeliveryRepository(db),
+		Outbox:        NewPgOutboxRepository(db),
+		Audit:         NewPgAuditRepository(db),
+		UoW:           NewPgUnitOfWork(db),
+	}
+}
+
+func NewSQLiteStore(db *sql.DB) *Store {
+	return &Store{
+		PRs:           NewSQLitePRRepository(db),
+		Users:         NewSQLiteUserRepository(db),
+		Teams:         NewSQLiteTeamRepository(db),
+		Tokens:        NewSQLiteTokenRepository(db),
+		ExternalPRs:   NewSQLiteExternalPRRepository(db),
+		Subscriptions: NewSQLiteSubscriptionRepository(db),
+		Deliveries:    NewSQLiteDeliveryRepository(db),
+		Outbox:        NewSQLiteOutboxRepository(db),
+		Audit:         NewSQLiteAuditRepository(db),
+		UoW:           NewSQLiteUnitOfWork(db),
+	}
+}
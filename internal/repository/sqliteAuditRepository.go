@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"PR-appointer/internal/entity"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// sqliteTimeLayout - формат strftime('%Y-%m-%d %H:%M:%f'), в котором SQLite хранит время
+const sqliteTimeLayout = "2006-01-02 15:04:05.000"
+
+type SQLiteAuditRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteAuditRepository(db *sql.DB) *SQLiteAuditRepository {
+	return &SQLiteAuditRepository{db: db}
+}
+
+func (r *SQLiteAuditRepository) conn(ctx context.Context) SQLDBTX {
+	return sqliteExecutor(ctx, r.db)
+}
+
+func (r *SQLiteAuditRepository) Add(ctx context.Context, entry *entity.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (actor, action, pr_id, user_id, team_id, before_state, after_state, reason)
+		VALUES (?1, ?2, NULLIF(?3, 0), NULLIF(?4, 0), NULLIF(?5, 0), ?6, ?7, NULLIF(?8, ''))
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query,
+		entry.Actor,
+		entry.Action,
+		entry.PullRequestID,
+		entry.UserID,
+		entry.TeamID,
+		sqliteNullJSON(entry.Before),
+		sqliteNullJSON(entry.After),
+		entry.Reason,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// List возвращает записи, новые первыми.
+func (r *SQLiteAuditRepository) List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error) {
+	query := `
+		SELECT id, actor, action, COALESCE(pr_id, 0), COALESCE(user_id, 0), COALESCE(team_id, 0),
+			before_state, after_state, COALESCE(reason, ''), created_at
+		FROM audit_log
+		WHERE (?1 = 0 OR pr_id = ?1)
+			AND (?2 = 0 OR user_id = ?2)
+			AND (?3 = 0 OR team_id = ?3)
+			AND (?4 IS NULL OR created_at >= ?4)
+			AND (?5 IS NULL OR created_at < ?5)
+		ORDER BY id DESC
+		LIMIT ?6
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query,
+		filter.PullRequestID,
+		filter.UserID,
+		filter.TeamID,
+		sqliteTime(filter.From),
+		sqliteTime(filter.To),
+		filter.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []entity.AuditEntry{}
+	for rows.Next() {
+		var e entity.AuditEntry
+		var before, after sql.NullString
+		if err := rows.Scan(
+			&e.ID,
+			&e.Actor,
+			&e.Action,
+			&e.PullRequestID,
+			&e.UserID,
+			&e.TeamID,
+			&before,
+			&after,
+			&e.Reason,
+			&e.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		if before.Valid {
+			e.Before = []byte(before.String)
+		}
+		if after.Valid {
+			e.After = []byte(after.String)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+func sqliteNullJSON(state []byte) any {
+	if len(state) == 0 {
+		return nil
+	}
+	return string(state)
+}
+
+// sqliteTime приводит время к формату, в котором его можно сравнивать со значениями в базе
+func sqliteTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(sqliteTimeLayout)
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"PR-appointer/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type SQLiteDeliveryRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteDeliveryRepository(db *sql.DB) *SQLiteDeliveryRepository {
+	return &SQLiteDeliveryRepository{db: db}
+}
+
+func (r *SQLiteDeliveryRepository) conn(ctx context.Context) SQLDBTX {
+	return sqliteExecutor(ctx, r.db)
+}
+
+// Enqueue ставит событие в очередь подписки; повторная постановка того же события ничего не меняет.
+func (r *SQLiteDeliveryRepository) Enqueue(ctx context.Context, subscriptionID int, eventID, eventType string, payload []byte) error {
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+		VALUES (?1, ?2, ?3, ?4)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, subscriptionID, eventID, eventType, string(payload)); err != nil {
+		return fmt.Errorf("failed to enqueue delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDue забирает доставки, которые пора отправить, и откладывает их на lease,
+// чтобы после падения процесса доставка повторилась.
+func (r *SQLiteDeliveryRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = strftime('%Y-%m-%d %H:%M:%f', 'now', '+' || ?2 || ' seconds')
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= strftime('%Y-%m-%d %H:%M:%f', 'now')
+			ORDER BY next_attempt_at, id
+			LIMIT ?1
+		)
+		RETURNING ` + deliveryColumns
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+
+	return scanSQLiteDeliveries(rows)
+}
+
+func (r *SQLiteDeliveryRepository) RecordAttempt(ctx context.Context, deliveryID int, attempt entity.DeliveryAttempt) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1,
+			status = ?2,
+			response_status = NULLIF(?3, 0),
+			last_error = NULLIF(?4, ''),
+			next_attempt_at = CASE WHEN ?2 = 'pending'
+				THEN strftime('%Y-%m-%d %H:%M:%f', 'now', '+' || ?5 || ' seconds') END,
+			delivered_at = CASE WHEN ?2 = 'succeeded' THEN strftime('%Y-%m-%d %H:%M:%f', 'now') END
+		WHERE id = ?1
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, deliveryID, deliveryStatus(attempt), attempt.ResponseStatus, attempt.Error, attempt.RetryIn.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to record delivery attempt: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteDeliveryRepository) List(ctx context.Context, filter entity.DeliveryFilter) ([]entity.WebhookDelivery, error) {
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries
+		WHERE (?1 = 0 OR subscription_id = ?1) AND (?2 = '' OR status = ?2)
+		ORDER BY id DESC
+		LIMIT ?3
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, filter.SubscriptionID, filter.Status, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+
+	return scanSQLiteDeliveries(rows)
+}
+
+// Redeliver ставит доставку в очередь заново с полным числом попыток.
+func (r *SQLiteDeliveryRepository) Redeliver(ctx context.Context, deliveryID int) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = strftime('%Y-%m-%d %H:%M:%f', 'now'), last_error = NULL
+		WHERE id = ?1
+	`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, deliveryID)
+	if err != nil {
+		return fmt.Errorf("failed to redeliver: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to redeliver: %w", err)
+	}
+	if affected == 0 {
+		return errors.New("delivery not found")
+	}
+
+	return nil
+}
+
+func scanSQLiteDeliveries(rows *sql.Rows) ([]entity.WebhookDelivery, error) {
+	defer rows.Close()
+
+	deliveries := []entity.WebhookDelivery{}
+	for rows.Next() {
+		var d entity.WebhookDelivery
+		var payload string
+		if err := rows.Scan(
+			&d.ID,
+			&d.SubscriptionID,
+			&d.EventID,
+			&d.EventType,
+			&payload,
+			&d.Status,
+			&d.Attempts,
+			&d.NextAttemptAt,
+			&d.ResponseStatus,
+			&d.LastError,
+			&d.CreatedAt,
+			&d.DeliveredAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		d.Payload = []byte(payload)
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type SQLiteExternalPRRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteExternalPRRepository(db *sql.DB) *SQLiteExternalPRRepository {
+	return &SQLiteExternalPRRepository{db: db}
+}
+
+func (r *SQLiteExternalPRRepository) conn(ctx context.Context) SQLDBTX {
+	return sqliteExecutor(ctx, r.db)
+}
+
+func (r *SQLiteExternalPRRepository) GetPRID(ctx context.Context, provider, repository string, number int) (int, error) {
+	query := `
+		SELECT pr_id FROM external_pull_requests
+		WHERE provider = ?1 AND repository = ?2 AND number = ?3
+	`
+
+	var prID int
+	err := r.conn(ctx).QueryRowContext(ctx, query, provider, repository, number).Scan(&prID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errors.New("external PR not found")
+		}
+		return 0, fmt.Errorf("failed to get external PR: %w", err)
+	}
+
+	return prID, nil
+}
+
+func (r *SQLiteExternalPRRepository) Link(ctx context.Context, provider, repository string, number, prID int) error {
+	query := `
+		INSERT INTO external_pull_requests (provider, repository, number, pr_id)
+		VALUES (?1, ?2, ?3, ?4)
+	`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, provider, repository, number, prID); err != nil {
+		if isSQLiteUniqueViolation(err) {
+			return errors.New("external PR already linked")
+		}
+		return fmt.Errorf("failed to link external PR: %w", err)
+	}
+
+	return nil
+}
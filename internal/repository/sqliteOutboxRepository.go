@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"PR-appointer/internal/entity"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+)
+
+type SQLiteOutboxRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteOutboxRepository(db *sql.DB) *SQLiteOutboxRepository {
+	return &SQLiteOutboxRepository{db: db}
+}
+
+func (r *SQLiteOutboxRepository) conn(ctx context.Context) SQLDBTX {
+	return sqliteExecutor(ctx, r.db)
+}
+
+func (r *SQLiteOutboxRepository) Add(ctx context.Context, eventID, eventType string, payload []byte) error {
+	query := `
+		INSERT INTO outbox_events (event_id, event_type, payload)
+		VALUES (?1, ?2, ?3)
+	`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, eventID, eventType, string(payload)); err != nil {
+		return fmt.Errorf("failed to add outbox event: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDue забирает необработанные события по порядку записи и откладывает их на lease,
+// чтобы после падения процесса событие обработалось снова.
+func (r *SQLiteOutboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxEvent, error) {
+	query := `
+		UPDATE outbox_events
+		SET next_attempt_at = strftime('%Y-%m-%d %H:%M:%f', 'now', '+' || ?2 || ' seconds')
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE processed_at IS NULL AND failed_at IS NULL AND next_attempt_at <= strftime('%Y-%m-%d %H:%M:%f', 'now')
+			ORDER BY id
+			LIMIT ?1
+		)
+		RETURNING id, event_id, event_type, payload, attempts, created_at
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []entity.OutboxEvent{}
+	for rows.Next() {
+		var e entity.OutboxEvent
+		var payload string
+		if err := rows.Scan(&e.ID, &e.EventID, &e.EventType, &payload, &e.Attempts, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		e.Payload = []byte(payload)
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING в SQLite не гарантирует порядок строк
+	slices.SortFunc(events, func(a, b entity.OutboxEvent) int { return a.ID - b.ID })
+
+	return events, nil
+}
+
+func (r *SQLiteOutboxRepository) MarkProcessed(ctx context.Context, id int) error {
+	query := `
+		UPDATE outbox_events
+		SET processed_at = strftime('%Y-%m-%d %H:%M:%f', 'now'), attempts = attempts + 1, last_error = NULL
+		WHERE id = ?1
+	`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to mark outbox event processed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteOutboxRepository) Reschedule(ctx context.Context, id int, retryIn time.Duration, lastError string) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1,
+			next_attempt_at = strftime('%Y-%m-%d %H:%M:%f', 'now', '+' || ?2 || ' seconds'),
+			last_error = ?3
+		WHERE id = ?1
+	`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, id, retryIn.Seconds(), lastError); err != nil {
+		return fmt.Errorf("failed to reschedule outbox event: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed прекращает повторы события; оно остается в outbox с последней ошибкой.
+func (r *SQLiteOutboxRepository) MarkFailed(ctx context.Context, id int, lastError string) error {
+	query := `
+		UPDATE outbox_events
+		SET failed_at = strftime('%Y-%m-%d %H:%M:%f', 'now'), attempts = attempts + 1, last_error = ?2
+		WHERE id = ?1
+	`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, id, lastError); err != nil {
+		return fmt.Errorf("failed to mark outbox event failed: %w", err)
+	}
+
+	return nil
+}
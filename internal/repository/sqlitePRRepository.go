@@ -0,0 +1,484 @@
+package repository
+
+import (
+	"PR-appointer/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type SQLitePRRepository struct {
+	db *sql.DB
+}
+
+func NewSQLitePRRepository(db *sql.DB) *SQLitePRRepository {
+	return &SQLitePRRepository{db: db}
+}
+
+func (r *SQLitePRRepository) conn(ctx context.Context) SQLDBTX {
+	return sqliteExecutor(ctx, r.db)
+}
+
+func (r *SQLitePRRepository) Create(ctx context.Context, id int, title string, authorID, teamID int, status string, degraded bool) (*entity.PullRequest, error) {
+	// id 0 - PR без своего id (например, из вебхука): NULL в INTEGER PRIMARY KEY получает
+	// следующий свободный id при вставке, под блокировкой записи SQLite
+	query := `
+		INSERT INTO pull_requests (id, title, author_id, team_id, status, degraded)
+		VALUES (NULLIF(?1, 0), ?2, ?3, ?4, ?5, ?6)
+		RETURNING id, title, author_id, team_id, status, created_at, updated_at, degraded
+	`
+
+	pr := entity.PullRequest{}
+	err := r.conn(ctx).QueryRowContext(ctx, query, id, title, authorID, teamID, status, degraded).Scan(
+		&pr.ID,
+		&pr.Title,
+		&pr.AuthorID,
+		&pr.TeamID,
+		&pr.Status,
+		&pr.CreatedAt,
+		&pr.UpdatedAt,
+		&pr.Degraded,
+	)
+
+	if err != nil {
+		if isSQLiteUniqueViolation(err) {
+			return nil, errors.New("PR already exists")
+		}
+		return nil, fmt.Errorf("failed to create PR: %w", err)
+	}
+
+	return &pr, nil
+}
+
+func (r *SQLitePRRepository) GetByID(ctx context.Context, prID int) (*entity.PullRequest, error) {
+	query := `
+		SELECT id, title, author_id, team_id, status, created_at, updated_at, merged_at, closed_at, degraded
+		FROM pull_requests
+		WHERE id = ?1
+	`
+
+	pr := entity.PullRequest{}
+	err := r.conn(ctx).QueryRowContext(ctx, query, prID).Scan(
+		&pr.ID,
+		&pr.Title,
+		&pr.AuthorID,
+		&pr.TeamID,
+		&pr.Status,
+		&pr.CreatedAt,
+		&pr.UpdatedAt,
+		&pr.MergedAt,
+		&pr.ClosedAt,
+		&pr.Degraded,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("PR not found")
+		}
+		return nil, fmt.Errorf("failed to get PR: %w", err)
+	}
+
+	return &pr, nil
+}
+
+// GetByIDForUpdate совпадает с GetByID: в SQLite нет блокировки строк,
+// а транзакции и так выполняются по очереди (см. SQLiteUnitOfWork).
+func (r *SQLitePRRepository) GetByIDForUpdate(ctx context.Context, prID int) (*entity.PullRequest, error) {
+	return r.GetByID(ctx, prID)
+}
+
+func (r *SQLitePRRepository) UpdateStatus(ctx context.Context, prID int, status string) (*entity.PullRequest, error) {
+	query := `
+		UPDATE pull_requests
+		SET status = ?1, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now'),
+			merged_at = CASE WHEN ?1 = 'MERGED' THEN strftime('%Y-%m-%d %H:%M:%f', 'now') ELSE merged_at END,
+			closed_at = CASE WHEN ?1 = 'CLOSED' THEN strftime('%Y-%m-%d %H:%M:%f', 'now') ELSE NULL END
+		WHERE id = ?2
+		RETURNING id, title, author_id, team_id, status, created_at, updated_at, merged_at, closed_at, degraded
+	`
+
+	pr := entity.PullRequest{}
+	err := r.conn(ctx).QueryRowContext(ctx, query, status, prID).Scan(
+		&pr.ID,
+		&pr.Title,
+		&pr.AuthorID,
+		&pr.TeamID,
+		&pr.Status,
+		&pr.CreatedAt,
+		&pr.UpdatedAt,
+		&pr.MergedAt,
+		&pr.ClosedAt,
+		&pr.Degraded,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("PR not found")
+		}
+		return nil, fmt.Errorf("failed to update PR status: %w", err)
+	}
+
+	return &pr, nil
+}
+
+func (r *SQLitePRRepository) SetDegraded(ctx context.Context, prID int, degraded bool) error {
+	query := `
+		UPDATE pull_requests
+		SET degraded = ?2, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		WHERE id = ?1
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, prID, degraded)
+	if err != nil {
+		return fmt.Errorf("failed to update PR degraded flag: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLitePRRepository) AddReviewer(ctx context.Context, prID int, reviewerID, teamID int, crossTeam bool) error {
+	query := `
+		INSERT INTO pr_reviewers (pr_id, reviewer_id, team_id, cross_team)
+		VALUES (?1, ?2, NULLIF(?3, 0), ?4)
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, prID, reviewerID, teamID, crossTeam)
+	if err != nil {
+		if isSQLiteUniqueViolation(err) {
+			return errors.New("reviewer is already assigned to this PR")
+		}
+		return fmt.Errorf("failed to add reviewer: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLitePRRepository) RemoveReviewer(ctx context.Context, prID int, reviewerID int) error {
+	query := `
+		DELETE FROM pr_reviewers
+		WHERE pr_id = ?1 AND reviewer_id = ?2
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, prID, reviewerID)
+	if err != nil {
+		return fmt.Errorf("failed to remove reviewer: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLitePRRepository) RemoveAllReviewers(ctx context.Context, prID int) error {
+	query := `
+		DELETE FROM pr_reviewers
+		WHERE pr_id = ?1
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, prID)
+	if err != nil {
+		return fmt.Errorf("failed to remove reviewers: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLitePRRepository) GetReviewers(ctx context.Context, prID int) ([]entity.ReviewerResponse, error) {
+	query := `
+		SELECT u.id, u.username, u.is_active, COALESCE(t.id, 0), COALESCE(t.name, ''), prr.cross_team,
+			COALESCE(prr.verdict, ''), prr.reviewed_at, prr.assigned_at
+		FROM pr_reviewers prr
+		JOIN users u ON u.id = prr.reviewer_id
+		LEFT JOIN teams t ON t.id = COALESCE(prr.team_id, (
+			-- Для старых назначений без команды берем основную команду ревьювера
+			SELECT tm.team_id FROM team_members tm
+			WHERE tm.user_id = u.id
+			ORDER BY tm.is_primary DESC, tm.team_id
+			LIMIT 1
+		))
+		WHERE prr.pr_id = ?1
+		ORDER BY prr.assigned_at, prr.id
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reviewers: %w", err)
+	}
+	defer rows.Close()
+
+	var reviewers []entity.ReviewerResponse
+	for rows.Next() {
+		reviewer := entity.ReviewerResponse{}
+		err := rows.Scan(
+			&reviewer.UserID,
+			&reviewer.Username,
+			&reviewer.IsActive,
+			&reviewer.TeamID,
+			&reviewer.TeamName,
+			&reviewer.CrossTeam,
+			&reviewer.Verdict,
+			&reviewer.ReviewedAt,
+			&reviewer.AssignedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reviewer: %w", err)
+		}
+		reviewers = append(reviewers, reviewer)
+	}
+
+	return reviewers, rows.Err()
+}
+
+func (r *SQLitePRRepository) IsReviewerAssigned(ctx context.Context, prID int, reviewerID int) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM pr_reviewers
+			WHERE pr_id = ?1 AND reviewer_id = ?2
+		)
+	`
+
+	var exists bool
+	err := r.conn(ctx).QueryRowContext(ctx, query, prID, reviewerID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check reviewer assignment: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *SQLitePRRepository) GetPRsByReviewer(ctx context.Context, reviewerID int) ([]entity.PRSummary, error) {
+	query := `
+		SELECT pr.id, pr.title, pr.author_id, pr.status, COALESCE(prr.verdict, ''), prr.reviewed_at
+		FROM pull_requests pr
+		INNER JOIN pr_reviewers prr ON pr.id = prr.pr_id
+		WHERE prr.reviewer_id = ?1
+		ORDER BY pr.created_at DESC
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, reviewerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query PRs by reviewer: %w", err)
+	}
+	defer rows.Close()
+
+	var prs []entity.PRSummary
+	for rows.Next() {
+		pr := entity.PRSummary{}
+		err := rows.Scan(
+			&pr.PullRequestID,
+			&pr.PullRequestName,
+			&pr.AuthorID,
+			&pr.Status,
+			&pr.Verdict,
+			&pr.ReviewedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan PR: %w", err)
+		}
+		prs = append(prs, pr)
+	}
+
+	return prs, rows.Err()
+}
+
+func (r *SQLitePRRepository) SetVerdict(ctx context.Context, prID, reviewerID int, verdict string) error {
+	query := `
+		UPDATE pr_reviewers
+		SET verdict = ?3, reviewed_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		WHERE pr_id = ?1 AND reviewer_id = ?2
+	`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, prID, reviewerID, verdict)
+	if err != nil {
+		return fmt.Errorf("failed to set verdict: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to set verdict: %w", err)
+	}
+	if affected == 0 {
+		return errors.New("reviewer is not assigned to this PR")
+	}
+
+	return nil
+}
+
+func (r *SQLitePRRepository) CountApprovals(ctx context.Context, prID int) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM pr_reviewers
+		WHERE pr_id = ?1 AND verdict = 'APPROVED'
+	`
+
+	var count int
+	err := r.conn(ctx).QueryRowContext(ctx, query, prID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count approvals: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *SQLitePRRepository) GetLastAssignedAt(ctx context.Context, reviewerIDs []int) (map[int]time.Time, error) {
+	query := `
+		SELECT reviewer_id, MAX(assigned_at)
+		FROM pr_reviewers
+		WHERE reviewer_id IN (SELECT value FROM json_each(?1))
+		GROUP BY reviewer_id
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, sqliteIDs(reviewerIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query last assignments: %w", err)
+	}
+	defer rows.Close()
+
+	lastAssigned := make(map[int]time.Time)
+	for rows.Next() {
+		var reviewerID int
+		// MAX теряет тип колонки, поэтому время приходит строкой
+		var assignedAt string
+		if err := rows.Scan(&reviewerID, &assignedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan last assignment: %w", err)
+		}
+		lastAssigned[reviewerID], err = parseSQLiteTime(assignedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan last assignment: %w", err)
+		}
+	}
+
+	return lastAssigned, rows.Err()
+}
+
+func (r *SQLitePRRepository) GetOpenReviewCounts(ctx context.Context, reviewerIDs []int) (map[int]int, error) {
+	query := `
+		SELECT prr.reviewer_id, COUNT(*)
+		FROM pr_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pr_id
+		WHERE prr.reviewer_id IN (SELECT value FROM json_each(?1)) AND pr.status = 'OPEN'
+		GROUP BY prr.reviewer_id
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, sqliteIDs(reviewerIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open review counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int]int)
+	for rows.Next() {
+		var reviewerID, count int
+		if err := rows.Scan(&reviewerID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan open review count: %w", err)
+		}
+		counts[reviewerID] = count
+	}
+
+	return counts, rows.Err()
+}
+
+func (r *SQLitePRRepository) GetOpenAssignments(ctx context.Context, reviewerIDs []int) ([]entity.ReviewAssignment, error) {
+	query := `
+		SELECT prr.pr_id, prr.reviewer_id
+		FROM pr_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pr_id
+		WHERE prr.reviewer_id IN (SELECT value FROM json_each(?1)) AND pr.status = 'OPEN'
+		ORDER BY prr.pr_id, prr.reviewer_id
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, sqliteIDs(reviewerIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open assignments: %w", err)
+	}
+	defer rows.Close()
+
+	var assignments []entity.ReviewAssignment
+	for rows.Next() {
+		assignment := entity.ReviewAssignment{}
+		if err := rows.Scan(&assignment.PullRequestID, &assignment.ReviewerID); err != nil {
+			return nil, fmt.Errorf("failed to scan assignment: %w", err)
+		}
+		assignments = append(assignments, assignment)
+	}
+
+	return assignments, rows.Err()
+}
+
+func (r *SQLitePRRepository) List(ctx context.Context, filter entity.PRListFilter) ([]entity.PRListItem, error) {
+	column, direction, cmp, err := prListOrder(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []any{
+		filter.Status,
+		filter.AuthorID,
+		filter.ReviewerID,
+		filter.TeamID,
+		likePattern(filter.Title),
+		sqliteTime(filter.CreatedFrom),
+		sqliteTime(filter.CreatedTo),
+		sqliteTime(filter.MergedFrom),
+		sqliteTime(filter.MergedTo),
+		filter.Limit,
+	}
+
+	after := "1"
+	if filter.After != nil {
+		value := prListAfter(filter)
+		if t, ok := value.(time.Time); ok {
+			value = sqliteTime(&t)
+		}
+		after = fmt.Sprintf("(pr.%s, pr.id) %s (?11, ?12)", column, cmp)
+		args = append(args, value, filter.After.ID)
+	}
+
+	// LIKE в SQLite не различает регистр латиницы, как ILIKE в PostgreSQL
+	query := fmt.Sprintf(`
+		SELECT pr.id, pr.title, pr.author_id, COALESCE(t.name, ''), pr.status, pr.degraded,
+			pr.created_at, pr.updated_at, pr.merged_at, pr.closed_at
+		FROM pull_requests pr
+		LEFT JOIN teams t ON t.id = pr.team_id
+		WHERE (?1 = '' OR pr.status = ?1)
+			AND (?2 = 0 OR pr.author_id = ?2)
+			AND (?3 = 0 OR EXISTS (SELECT 1 FROM pr_reviewers prr WHERE prr.pr_id = pr.id AND prr.reviewer_id = ?3))
+			AND (?4 = 0 OR pr.team_id = ?4)
+			AND (?5 = '' OR pr.title LIKE ?5 ESCAPE '\')
+			AND (?6 IS NULL OR pr.created_at >= ?6)
+			AND (?7 IS NULL OR pr.created_at < ?7)
+			AND (?8 IS NULL OR pr.merged_at >= ?8)
+			AND (?9 IS NULL OR pr.merged_at < ?9)
+			AND %[3]s
+		ORDER BY pr.%[1]s %[2]s, pr.id %[2]s
+		LIMIT ?10
+	`, column, direction, after)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PRs: %w", err)
+	}
+	defer rows.Close()
+
+	prs := []entity.PRListItem{}
+	for rows.Next() {
+		var pr entity.PRListItem
+		if err := rows.Scan(
+			&pr.PullRequestID,
+			&pr.PullRequestName,
+			&pr.AuthorID,
+			&pr.TeamName,
+			&pr.Status,
+			&pr.Degraded,
+			&pr.CreatedAt,
+			&pr.UpdatedAt,
+			&pr.MergedAt,
+			&pr.ClosedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan PR: %w", err)
+		}
+		prs = append(prs, pr)
+	}
+
+	return prs, rows.Err()
+}
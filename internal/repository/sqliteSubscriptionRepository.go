@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"PR-appointer/internal/entity"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type SQLiteSubscriptionRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteSubscriptionRepository(db *sql.DB) *SQLiteSubscriptionRepository {
+	return &SQLiteSubscriptionRepository{db: db}
+}
+
+func (r *SQLiteSubscriptionRepository) conn(ctx context.Context) SQLDBTX {
+	return sqliteExecutor(ctx, r.db)
+}
+
+func (r *SQLiteSubscriptionRepository) Create(ctx context.Context, url, secret string, events []string) (*entity.WebhookSubscription, error) {
+	query := `
+		INSERT INTO webhook_subscriptions (url, secret, events)
+		VALUES (?1, ?2, ?3)
+		RETURNING id, url, secret, events, created_at
+	`
+
+	eventsJSON, err := json.Marshal(events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	return scanSQLiteSubscription(r.conn(ctx).QueryRowContext(ctx, query, url, secret, string(eventsJSON)))
+}
+
+func (r *SQLiteSubscriptionRepository) GetByID(ctx context.Context, subscriptionID int) (*entity.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, secret, events, created_at
+		FROM webhook_subscriptions
+		WHERE id = ?1
+	`
+
+	subscription, err := scanSQLiteSubscription(r.conn(ctx).QueryRowContext(ctx, query, subscriptionID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("subscription not found")
+		}
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+func (r *SQLiteSubscriptionRepository) List(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, secret, events, created_at
+		FROM webhook_subscriptions
+		ORDER BY id
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []entity.WebhookSubscription{}
+	for rows.Next() {
+		subscription, err := scanSQLiteSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func (r *SQLiteSubscriptionRepository) Delete(ctx context.Context, subscriptionID int) error {
+	result, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = ?1`, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+	if affected == 0 {
+		return errors.New("subscription not found")
+	}
+
+	return nil
+}
+
+// scanSQLiteSubscription читает подписку; события хранятся JSON-массивом.
+func scanSQLiteSubscription(row interface{ Scan(dest ...any) error }) (*entity.WebhookSubscription, error) {
+	subscription := entity.WebhookSubscription{}
+	var events string
+	if err := row.Scan(
+		&subscription.ID,
+		&subscription.URL,
+		&subscription.Secret,
+		&events,
+		&subscription.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(events), &subscription.Events); err != nil {
+		return nil, fmt.Errorf("failed to parse subscription events: %w", err)
+	}
+
+	return &subscription, nil
+}
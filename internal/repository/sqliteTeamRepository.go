@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"PR-appointer/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type SQLiteTeamRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteTeamRepository(db *sql.DB) *SQLiteTeamRepository {
+	return &SQLiteTeamRepository{db: db}
+}
+
+func (r *SQLiteTeamRepository) conn(ctx context.Context) SQLDBTX {
+	return sqliteExecutor(ctx, r.db)
+}
+
+func (r *SQLiteTeamRepository) Create(ctx context.Context, name string) (*entity.Team, error) {
+	query := `
+		INSERT INTO teams (name)
+		VALUES (?1)
+		RETURNING id, name, created_at, updated_at
+	`
+
+	team := entity.Team{}
+	err := r.conn(ctx).QueryRowContext(ctx, query, name).Scan(
+		&team.ID,
+		&team.Name,
+		&team.CreatedAt,
+		&team.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create team: %w", err)
+	}
+
+	return &team, nil
+}
+
+func (r *SQLiteTeamRepository) GetByName(ctx context.Context, name string) (*entity.Team, error) {
+	query := `
+		SELECT id, name, created_at, updated_at
+		FROM teams
+		WHERE name = ?1
+	`
+
+	return r.getTeam(ctx, query, name)
+}
+
+func (r *SQLiteTeamRepository) GetByID(ctx context.Context, teamID int) (*entity.Team, error) {
+	query := `
+		SELECT id, name, created_at, updated_at
+		FROM teams
+		WHERE id = ?1
+	`
+
+	return r.getTeam(ctx, query, teamID)
+}
+
+func (r *SQLiteTeamRepository) getTeam(ctx context.Context, query string, arg any) (*entity.Team, error) {
+	team := entity.Team{}
+	err := r.conn(ctx).QueryRowContext(ctx, query, arg).Scan(
+		&team.ID,
+		&team.Name,
+		&team.CreatedAt,
+		&team.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("team not found")
+		}
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+
+	return &team, nil
+}
+
+func (r *SQLiteTeamRepository) AddMember(ctx context.Context, teamID, userID, reviewWeight int) error {
+	query := `
+		INSERT INTO team_members (team_id, user_id, review_weight)
+		VALUES (?1, ?2, ?3)
+		ON CONFLICT (team_id, user_id) DO UPDATE SET review_weight = excluded.review_weight
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, teamID, userID, reviewWeight)
+	if err != nil {
+		return fmt.Errorf("failed to add team member: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteTeamRepository) GetMembers(ctx context.Context, teamID int) ([]entity.UserResponse, error) {
+	query := `
+		SELECT u.id, u.username, u.is_active, t.name
+		FROM users u
+		JOIN team_members tm ON u.id = tm.user_id
+		JOIN teams t on tm.team_id = t.id
+		WHERE tm.team_id = ?1
+		ORDER BY u.id
+	`
+
+	return r.queryMembers(ctx, query, teamID)
+}
+
+func (r *SQLiteTeamRepository) GetActiveMembers(ctx context.Context, teamID int, excludeUserID *int) ([]entity.UserResponse, error) {
+	query := `
+		SELECT u.id, u.username, u.is_active, t.name
+		FROM users u
+		JOIN team_members tm ON u.id = tm.user_id
+		JOIN teams t on tm.team_id = t.id
+		WHERE tm.team_id = ?1 AND u.is_active = TRUE
+	`
+
+	args := []interface{}{teamID}
+	if excludeUserID != nil {
+		query += ` AND u.id != ?2`
+		args = append(args, *excludeUserID)
+	}
+
+	query += ` ORDER BY u.id`
+
+	return r.queryMembers(ctx, query, args...)
+}
+
+func (r *SQLiteTeamRepository) queryMembers(ctx context.Context, query string, args ...any) ([]entity.UserResponse, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query team members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []entity.UserResponse
+	for rows.Next() {
+		user := entity.UserResponse{}
+		err := rows.Scan(
+			&user.UserID,
+			&user.Username,
+			&user.IsActive,
+			&user.TeamName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan team member: %w", err)
+		}
+		members = append(members, user)
+	}
+
+	return members, rows.Err()
+}
+
+func (r *SQLiteTeamRepository) SetPrimaryTeam(ctx context.Context, userID, teamID int) error {
+	query := `
+		UPDATE team_members
+		SET is_primary = (team_id = ?2)
+		WHERE user_id = ?1
+	`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, userID, teamID)
+	if err != nil {
+		return fmt.Errorf("failed to set primary team: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SQLiteTeamRepository) GetMemberWeights(ctx context.Context, teamID int) (map[int]int, error) {
+	query := `
+		SELECT user_id, review_weight
+		FROM team_members
+		WHERE team_id = ?1
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query member weights: %w", err)
+	}
+	defer rows.Close()
+
+	weights := make(map[int]int)
+	for rows.Next() {
+		var userID, weight int
+		if err := rows.Scan(&userID, &weight); err != nil {
+			return nil, fmt.Errorf("failed to scan member weight: %w", err)
+		}
+		weights[userID] = weight
+	}
+
+	return weights, rows.Err()
+}
+
+func (r *SQLiteTeamRepository) GetSettings(ctx context.Context, teamID int) (*entity.TeamSettings, error) {
+	query := `
+		SELECT team_id, reviewer_strategy, reviewers_per_pr, min_reviewers, fail_on_insufficient, required_approvals, max_open_reviews, updated_at
+		FROM team_settings
+		WHERE team_id = ?1
+	`
+
+	settings := entity.TeamSettings{}
+	err := r.conn(ctx).QueryRowContext(ctx, query, teamID).Scan(
+		&settings.TeamID,
+		&settings.ReviewerStrategy,
+		&settings.ReviewersPerPR,
+		&settings.MinReviewers,
+		&settings.FailOnInsufficient,
+		&settings.RequiredApprovals,
+		&settings.MaxOpenReviews,
+		&settings.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Настройки не заданы - используем значения по умолчанию
+			return &entity.TeamSettings{
+				TeamID:           teamID,
+				ReviewerStrategy: entity.StrategyRandom,
+				ReviewersPerPR:   entity.DefaultReviewersPerPR,
+			}, nil
+		}
+		return nil, fmt.Errorf("failed to get team settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+func (r *SQLiteTeamRepository) UpsertSettings(ctx context.Context, settings *entity.TeamSettings) (*entity.TeamSettings, error) {
+	query := `
+		INSERT INTO team_settings (team_id, reviewer_strategy, reviewers_per_pr, min_reviewers, fail_on_insufficient, required_approvals, max_open_reviews)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7)
+		ON CONFLICT (team_id)
+		DO UPDATE SET reviewer_strategy = excluded.reviewer_strategy,
+			reviewers_per_pr = excluded.reviewers_per_pr,
+			min_reviewers = excluded.min_reviewers,
+			fail_on_insufficient = excluded.fail_on_insufficient,
+			required_approvals = excluded.required_approvals,
+			max_open_reviews = excluded.max_open_reviews,
+			updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		RETURNING team_id, reviewer_strategy, reviewers_per_pr, min_reviewers, fail_on_insufficient, required_approvals, max_open_reviews, updated_at
+	`
+
+	saved := entity.TeamSettings{}
+	err := r.conn(ctx).QueryRowContext(ctx, query,
+		settings.TeamID,
+		settings.ReviewerStrategy,
+		settings.ReviewersPerPR,
+		settings.MinReviewers,
+		settings.FailOnInsufficient,
+		settings.RequiredApprovals,
+		settings.MaxOpenReviews,
+	).Scan(
+		&saved.TeamID,
+		&saved.ReviewerStrategy,
+		&saved.ReviewersPerPR,
+		&saved.MinReviewers,
+		&saved.FailOnInsufficient,
+		&saved.RequiredApprovals,
+		&saved.MaxOpenReviews,
+		&saved.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to save team settings: %w", err)
+	}
+
+	return &saved, nil
+}
+
+func (r *SQLiteTeamRepository) GetFallbackTeams(ctx context.Context, teamID int) ([]entity.Team, error) {
+	query := `
+		SELECT t.id, t.name, t.created_at, t.updated_at
+		FROM team_fallbacks tf
+		JOIN teams t ON t.id = tf.fallback_team_id
+		WHERE tf.team_id = ?1
+		ORDER BY tf.priority, t.id
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fallback teams: %w", err)
+	}
+	defer rows.Close()
+
+	var teams []entity.Team
+	for rows.Next() {
+		team := entity.Team{}
+		err := rows.Scan(
+			&team.ID,
+			&team.Name,
+			&team.CreatedAt,
+			&team.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan fallback team: %w", err)
+		}
+		teams = append(teams, team)
+	}
+
+	return teams, rows.Err()
+}
+
+func (r *SQLiteTeamRepository) SetFallbackTeams(ctx context.Context, teamID int, fallbackTeamIDs []int) error {
+	query := `
+		DELETE FROM team_fallbacks
+		WHERE team_id = ?1
+	`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, teamID); err != nil {
+		return fmt.Errorf("failed to clear fallback teams: %w", err)
+	}
+
+	query = `
+		INSERT INTO team_fallbacks (team_id, fallback_team_id, priority)
+		VALUES (?1, ?2, ?3)
+	`
+
+	for priority, fallbackTeamID := range fallbackTeamIDs {
+		if _, err := r.conn(ctx).ExecContext(ctx, query, teamID, fallbackTeamID, priority); err != nil {
+			return fmt.Errorf("failed to add fallback team: %w", err)
+		}
+	}
+
+	return nil
+}
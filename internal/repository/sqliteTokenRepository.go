@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"PR-appointer/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type SQLiteTokenRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteTokenRepository(db *sql.DB) *SQLiteTokenRepository {
+	return &SQLiteTokenRepository{db: db}
+}
+
+func (r *SQLiteTokenRepository) conn(ctx context.Context) SQLDBTX {
+	return sqliteExecutor(ctx, r.db)
+}
+
+func (r *SQLiteTokenRepository) Create(ctx context.Context, name, tokenHash, role string, userID *int) (*entity.APIToken, error) {
+	query := `
+		INSERT INTO api_tokens (name, token_hash, role, user_id)
+		VALUES (?1, ?2, ?3, ?4)
+		RETURNING id, name, token_hash, role, user_id, created_at
+	`
+
+	token := entity.APIToken{}
+	err := r.conn(ctx).QueryRowContext(ctx, query, name, tokenHash, role, userID).Scan(
+		&token.ID,
+		&token.Name,
+		&token.TokenHash,
+		&token.Role,
+		&token.UserID,
+		&token.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token: %w", err)
+	}
+
+	return &token, nil
+}
+
+func (r *SQLiteTokenRepository) GetPrincipal(ctx context.Context, tokenHash string) (*entity.Principal, error) {
+	// Отозванные токены и токены деактивированных пользователей не действуют
+	query := `
+		SELECT api_tokens.id, api_tokens.role, COALESCE(users.id, 0), COALESCE(users.username, '')
+		FROM api_tokens
+		LEFT JOIN users ON users.id = api_tokens.user_id
+		WHERE api_tokens.token_hash = ?1
+			AND api_tokens.revoked_at IS NULL
+			AND (api_tokens.user_id IS NULL OR users.is_active)
+	`
+
+	principal := entity.Principal{}
+	err := r.conn(ctx).QueryRowContext(ctx, query, tokenHash).Scan(
+		&principal.TokenID,
+		&principal.Role,
+		&principal.UserID,
+		&principal.Username,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("token not found")
+		}
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+
+	return &principal, nil
+}
+
+func (r *SQLiteTokenRepository) Revoke(ctx context.Context, tokenID int) error {
+	query := `
+		UPDATE api_tokens
+		SET revoked_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		WHERE id = ?1 AND revoked_at IS NULL
+	`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, tokenID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	if affected == 0 {
+		return errors.New("token not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// SQLDBTX - общий интерфейс *sql.DB и *sql.Tx.
+type SQLDBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type sqliteTxKey struct{}
+
+// SQLiteUnitOfWork выполняет вызовы SQLite-репозиториев в одной транзакции.
+// Транзакции открываются как BEGIN IMMEDIATE (_txlock=immediate в DSN) и выполняются
+// по очереди - это заменяет SELECT ... FOR UPDATE из Postgres.
+type SQLiteUnitOfWork struct {
+	db *sql.DB
+}
+
+func NewSQLiteUnitOfWork(db *sql.DB) *SQLiteUnitOfWork {
+	return &SQLiteUnitOfWork{db: db}
+}
+
+// Do открывает транзакцию, выполняет fn и фиксирует изменения, если fn не вернула ошибку.
+// Вложенный вызов переиспользует уже открытую транзакцию.
+func (u *SQLiteUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(sqliteTxKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := fn(context.WithValue(ctx, sqliteTxKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// sqliteExecutor возвращает транзакцию из контекста, а вне транзакции - базу.
+func sqliteExecutor(ctx context.Context, db *sql.DB) SQLDBTX {
+	if tx, ok := ctx.Value(sqliteTxKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// isSQLiteUniqueViolation сообщает, что запрос нарушил ограничение уникальности.
+func isSQLiteUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique ||
+		sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+}
+
+// sqliteIDs кодирует список id в JSON для json_each - в SQLite нет массивов.
+func sqliteIDs(ids []int) string {
+	if ids == nil {
+		ids = []int{}
+	}
+	encoded, _ := json.Marshal(ids)
+	return string(encoded)
+}
+
+// parseSQLiteTime разбирает время, которое агрегатные функции возвращают строкой.
+func parseSQLiteTime(value string) (time.Time, error) {
+	for _, layout := range sqlite3.SQLiteTimestampFormats {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unexpected time format: %s", value)
+}
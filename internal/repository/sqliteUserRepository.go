@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"PR-appointer/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type SQLiteUserRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteUserRepository(db *sql.DB) *SQLiteUserRepository {
+	return &SQLiteUserRepository{db: db}
+}
+
+func (r *SQLiteUserRepository) conn(ctx context.Context) SQLDBTX {
+	return sqliteExecutor(ctx, r.db)
+}
+
+func (r *SQLiteUserRepository) Create(ctx context.Context, username string, isActive bool) (*entity.User, error) {
+	query := `
+		INSERT INTO users (username, is_active)
+		VALUES (?1, ?2)
+		RETURNING id, username, is_active, created_at, updated_at
+	`
+
+	user := entity.User{}
+	err := r.conn(ctx).QueryRowContext(ctx, query, username, isActive).Scan(
+		&user.ID,
+		&user.Username,
+		&user.IsActive,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (r *SQLiteUserRepository) GetByID(ctx context.Context, userID int) (*entity.UserResponse, error) {
+	query := `
+		SELECT users.id, username, is_active, teams.name FROM users
+		JOIN team_members on team_members.user_id = users.id
+		JOIN teams on teams.id = team_members.team_id
+		WHERE users.id = ?1
+		ORDER BY team_members.is_primary DESC, team_members.team_id
+		LIMIT 1
+	`
+
+	user := entity.UserResponse{}
+	err := r.conn(ctx).QueryRowContext(ctx, query, userID).Scan(
+		&user.UserID,
+		&user.Username,
+		&user.IsActive,
+		&user.TeamName,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (r *SQLiteUserRepository) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
+	query := `
+		SELECT id, username, is_active, created_at, updated_at
+		FROM users
+		WHERE username = ?1
+	`
+
+	user := entity.User{}
+	err := r.conn(ctx).QueryRowContext(ctx, query, username).Scan(
+		&user.ID,
+		&user.Username,
+		&user.IsActive,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // User doesn't exist
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (r *SQLiteUserRepository) UpdateStatus(ctx context.Context, userID int, isActive bool) (*entity.UserResponse, error) {
+	query := `
+		UPDATE users
+		SET is_active = ?1, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		WHERE id = ?2
+	`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, isActive, userID); err != nil {
+		return nil, fmt.Errorf("failed to update user status: %w", err)
+	}
+
+	query = `
+		SELECT users.id, username, teams.name, is_active FROM users
+		JOIN team_members on team_members.user_id = users.id
+		JOIN teams on teams.id = team_members.team_id
+		WHERE users.id = ?1
+		ORDER BY team_members.is_primary DESC, team_members.team_id
+		LIMIT 1
+	`
+
+	user := entity.UserResponse{}
+	err := r.conn(ctx).QueryRowContext(ctx, query, userID).Scan(
+		&user.UserID,
+		&user.Username,
+		&user.TeamName,
+		&user.IsActive,
+	)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	return &user, nil
+}
+
+func (r *SQLiteUserRepository) Upsert(ctx context.Context, username string, isActive bool) (*entity.UserResponse, error) {
+	query := `
+		INSERT INTO users (username, is_active)
+		VALUES (?1, ?2)
+		ON CONFLICT (username)
+		DO UPDATE SET is_active = excluded.is_active, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		RETURNING id
+	`
+	userID := 0
+	err := r.conn(ctx).QueryRowContext(ctx, query, username, isActive).Scan(
+		&userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert user: %w", err)
+	}
+
+	// Новый пользователь еще не состоит в команде - имя команды пустое
+	query = `
+		SELECT users.id, username, is_active, COALESCE(teams.name, '') FROM users
+		LEFT JOIN team_members on team_members.user_id = users.id
+		LEFT JOIN teams on teams.id = team_members.team_id
+		WHERE users.id = ?1
+		ORDER BY team_members.is_primary DESC, team_members.team_id
+		LIMIT 1
+	`
+	user := entity.UserResponse{}
+	err = r.conn(ctx).QueryRowContext(ctx, query, userID).Scan(
+		&user.UserID,
+		&user.Username,
+		&user.IsActive,
+		&user.TeamName,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (r *SQLiteUserRepository) GetTeamsByUserID(ctx context.Context, userID int) ([]int, error) {
+	query := `
+		SELECT team_id
+		FROM team_members
+		WHERE user_id = ?1
+		ORDER BY is_primary DESC, team_id
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user teams: %w", err)
+	}
+	defer rows.Close()
+
+	var teamIDs []int
+	for rows.Next() {
+		var teamID int
+		if err := rows.Scan(&teamID); err != nil {
+			return nil, fmt.Errorf("failed to scan team id: %w", err)
+		}
+		teamIDs = append(teamIDs, teamID)
+	}
+
+	return teamIDs, rows.Err()
+}
+
+func (r *SQLiteUserRepository) SetActiveMany(ctx context.Context, userIDs []int, isActive bool) ([]entity.User, error) {
+	query := `
+		UPDATE users
+		SET is_active = ?2, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		WHERE id IN (SELECT value FROM json_each(?1))
+		RETURNING id, username, is_active, created_at, updated_at
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, sqliteIDs(userIDs), isActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update users status: %w", err)
+	}
+	defer rows.Close()
+
+	var users []entity.User
+	for rows.Next() {
+		user := entity.User{}
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.IsActive,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"PR-appointer/internal/entity"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgSubscriptionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPgSubscriptionRepository(db *pgxpool.Pool) *PgSubscriptionRepository {
+	return &PgSubscriptionRepository{db: db}
+}
+
+func (r *PgSubscriptionRepository) conn(ctx context.Context) DBTX {
+	return executor(ctx, r.db)
+}
+
+func (r *PgSubscriptionRepository) Create(ctx context.Context, url, secret string, events []string) (*entity.WebhookSubscription, error) {
+	query := `
+		INSERT INTO webhook_subscriptions (url, secret, events)
+		VALUES ($1, $2, $3)
+		RETURNING id, url, secret, events, created_at
+	`
+
+	subscription := entity.WebhookSubscription{}
+	err := r.conn(ctx).QueryRow(ctx, query, url, secret, events).Scan(
+		&subscription.ID,
+		&subscription.URL,
+		&subscription.Secret,
+		&subscription.Events,
+		&subscription.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	return &subscription, nil
+}
+
+func (r *PgSubscriptionRepository) GetByID(ctx context.Context, subscriptionID int) (*entity.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, secret, events, created_at
+		FROM webhook_subscriptions
+		WHERE id = $1
+	`
+
+	subscription := entity.WebhookSubscription{}
+	err := r.conn(ctx).QueryRow(ctx, query, subscriptionID).Scan(
+		&subscription.ID,
+		&subscription.URL,
+		&subscription.Secret,
+		&subscription.Events,
+		&subscription.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("subscription not found")
+		}
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	return &subscription, nil
+}
+
+func (r *PgSubscriptionRepository) List(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, secret, events, created_at
+		FROM webhook_subscriptions
+		ORDER BY id
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []entity.WebhookSubscription{}
+	for rows.Next() {
+		var subscription entity.WebhookSubscription
+		if err := rows.Scan(
+			&subscription.ID,
+			&subscription.URL,
+			&subscription.Secret,
+			&subscription.Events,
+			&subscription.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func (r *PgSubscriptionRepository) Delete(ctx context.Context, subscriptionID int) error {
+	tag, err := r.conn(ctx).Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New("subscription not found")
+	}
+
+	return nil
+}
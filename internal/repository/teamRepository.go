@@ -10,15 +10,19 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-type TeamRepository struct {
+type PgTeamRepository struct {
 	db *pgxpool.Pool
 }
 
-func NewTeamRepository(db *pgxpool.Pool) *TeamRepository {
-	return &TeamRepository{db: db}
+func NewPgTeamRepository(db *pgxpool.Pool) *PgTeamRepository {
+	return &PgTeamRepository{db: db}
 }
 
-func (r *TeamRepository) Create(ctx context.Context, name string) (*entity.Team, error) {
+func (r *PgTeamRepository) conn(ctx context.Context) DBTX {
+	return executor(ctx, r.db)
+}
+
+func (r *PgTeamRepository) Create(ctx context.Context, name string) (*entity.Team, error) {
 	query := `
 		INSERT INTO teams (name)
 		VALUES ($1)
@@ -26,7 +30,7 @@ func (r *TeamRepository) Create(ctx context.Context, name string) (*entity.Team,
 	`
 
 	team := entity.Team{}
-	err := r.db.QueryRow(ctx, query, name).Scan(
+	err := r.conn(ctx).QueryRow(ctx, query, name).Scan(
 		&team.ID,
 		&team.Name,
 		&team.CreatedAt,
@@ -40,7 +44,7 @@ func (r *TeamRepository) Create(ctx context.Context, name string) (*entity.Team,
 	return &team, nil
 }
 
-func (r *TeamRepository) GetByName(ctx context.Context, name string) (*entity.Team, error) {
+func (r *PgTeamRepository) GetByName(ctx context.Context, name string) (*entity.Team, error) {
 	query := `
 		SELECT id, name, created_at, updated_at
 		FROM teams
@@ -48,7 +52,7 @@ func (r *TeamRepository) GetByName(ctx context.Context, name string) (*entity.Te
 	`
 
 	team := entity.Team{}
-	err := r.db.QueryRow(ctx, query, name).Scan(
+	err := r.conn(ctx).QueryRow(ctx, query, name).Scan(
 		&team.ID,
 		&team.Name,
 		&team.CreatedAt,
@@ -65,7 +69,7 @@ func (r *TeamRepository) GetByName(ctx context.Context, name string) (*entity.Te
 	return &team, nil
 }
 
-func (r *TeamRepository) GetByID(ctx context.Context, teamID int) (*entity.Team, error) {
+func (r *PgTeamRepository) GetByID(ctx context.Context, teamID int) (*entity.Team, error) {
 	query := `
 		SELECT id, name, created_at, updated_at
 		FROM teams
@@ -73,7 +77,7 @@ func (r *TeamRepository) GetByID(ctx context.Context, teamID int) (*entity.Team,
 	`
 
 	team := entity.Team{}
-	err := r.db.QueryRow(ctx, query, teamID).Scan(
+	err := r.conn(ctx).QueryRow(ctx, query, teamID).Scan(
 		&team.ID,
 		&team.Name,
 		&team.CreatedAt,
@@ -90,14 +94,14 @@ func (r *TeamRepository) GetByID(ctx context.Context, teamID int) (*entity.Team,
 	return &team, nil
 }
 
-func (r *TeamRepository) AddMember(ctx context.Context, teamID, userID int) error {
+func (r *PgTeamRepository) AddMember(ctx context.Context, teamID, userID, reviewWeight int) error {
 	query := `
-		INSERT INTO team_members (team_id, user_id)
-		VALUES ($1, $2)
-		ON CONFLICT (team_id, user_id) DO NOTHING
+		INSERT INTO team_members (team_id, user_id, review_weight)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (team_id, user_id) DO UPDATE SET review_weight = EXCLUDED.review_weight
 	`
 
-	_, err := r.db.Exec(ctx, query, teamID, userID)
+	_, err := r.conn(ctx).Exec(ctx, query, teamID, userID, reviewWeight)
 	if err != nil {
 		return fmt.Errorf("failed to add team member: %w", err)
 	}
@@ -105,7 +109,7 @@ func (r *TeamRepository) AddMember(ctx context.Context, teamID, userID int) erro
 	return nil
 }
 
-func (r *TeamRepository) GetMembers(ctx context.Context, teamID int) ([]entity.UserResponse, error) {
+func (r *PgTeamRepository) GetMembers(ctx context.Context, teamID int) ([]entity.UserResponse, error) {
 	query := `
 		SELECT u.id, u.username, u.is_active, t.name
 		FROM users u
@@ -115,7 +119,7 @@ func (r *TeamRepository) GetMembers(ctx context.Context, teamID int) ([]entity.U
 		ORDER BY u.id
 	`
 
-	rows, err := r.db.Query(ctx, query, teamID)
+	rows, err := r.conn(ctx).Query(ctx, query, teamID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query team members: %w", err)
 	}
@@ -123,7 +127,7 @@ func (r *TeamRepository) GetMembers(ctx context.Context, teamID int) ([]entity.U
 	return ScanUserResponses(ctx, rows) // Используем общую функцию
 }
 
-func (r *TeamRepository) GetActiveMembers(ctx context.Context, teamID int, excludeUserID *int) ([]entity.UserResponse, error) {
+func (r *PgTeamRepository) GetActiveMembers(ctx context.Context, teamID int, excludeUserID *int) ([]entity.UserResponse, error) {
 	query := `
 		SELECT u.id, u.username, u.is_active, t.name
 		FROM users u
@@ -141,7 +145,7 @@ func (r *TeamRepository) GetActiveMembers(ctx context.Context, teamID int, exclu
 
 	query += ` ORDER BY u.id`
 
-	rows, err := r.db.Query(ctx, query, args...)
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query active team members: %w", err)
 	}
@@ -164,3 +168,176 @@ func (r *TeamRepository) GetActiveMembers(ctx context.Context, teamID int, exclu
 
 	return members, nil
 }
+
+func (r *PgTeamRepository) SetPrimaryTeam(ctx context.Context, userID, teamID int) error {
+	query := `
+		UPDATE team_members
+		SET is_primary = (team_id = $2)
+		WHERE user_id = $1
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query, userID, teamID)
+	if err != nil {
+		return fmt.Errorf("failed to set primary team: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PgTeamRepository) GetMemberWeights(ctx context.Context, teamID int) (map[int]int, error) {
+	query := `
+		SELECT user_id, review_weight
+		FROM team_members
+		WHERE team_id = $1
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query member weights: %w", err)
+	}
+	defer rows.Close()
+
+	weights := make(map[int]int)
+	for rows.Next() {
+		var userID, weight int
+		if err := rows.Scan(&userID, &weight); err != nil {
+			return nil, fmt.Errorf("failed to scan member weight: %w", err)
+		}
+		weights[userID] = weight
+	}
+
+	return weights, nil
+}
+
+func (r *PgTeamRepository) GetSettings(ctx context.Context, teamID int) (*entity.TeamSettings, error) {
+	query := `
+		SELECT team_id, reviewer_strategy, reviewers_per_pr, min_reviewers, fail_on_insufficient, required_approvals, max_open_reviews, updated_at
+		FROM team_settings
+		WHERE team_id = $1
+	`
+
+	settings := entity.TeamSettings{}
+	err := r.conn(ctx).QueryRow(ctx, query, teamID).Scan(
+		&settings.TeamID,
+		&settings.ReviewerStrategy,
+		&settings.ReviewersPerPR,
+		&settings.MinReviewers,
+		&settings.FailOnInsufficient,
+		&settings.RequiredApprovals,
+		&settings.MaxOpenReviews,
+		&settings.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Настройки не заданы - используем значения по умолчанию
+			return &entity.TeamSettings{
+				TeamID:           teamID,
+				ReviewerStrategy: entity.StrategyRandom,
+				ReviewersPerPR:   entity.DefaultReviewersPerPR,
+			}, nil
+		}
+		return nil, fmt.Errorf("failed to get team settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+func (r *PgTeamRepository) UpsertSettings(ctx context.Context, settings *entity.TeamSettings) (*entity.TeamSettings, error) {
+	query := `
+		INSERT INTO team_settings (team_id, reviewer_strategy, reviewers_per_pr, min_reviewers, fail_on_insufficient, required_approvals, max_open_reviews)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (team_id)
+		DO UPDATE SET reviewer_strategy = EXCLUDED.reviewer_strategy,
+			reviewers_per_pr = EXCLUDED.reviewers_per_pr,
+			min_reviewers = EXCLUDED.min_reviewers,
+			fail_on_insufficient = EXCLUDED.fail_on_insufficient,
+			required_approvals = EXCLUDED.required_approvals,
+			max_open_reviews = EXCLUDED.max_open_reviews,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING team_id, reviewer_strategy, reviewers_per_pr, min_reviewers, fail_on_insufficient, required_approvals, max_open_reviews, updated_at
+	`
+
+	saved := entity.TeamSettings{}
+	err := r.conn(ctx).QueryRow(ctx, query,
+		settings.TeamID,
+		settings.ReviewerStrategy,
+		settings.ReviewersPerPR,
+		settings.MinReviewers,
+		settings.FailOnInsufficient,
+		settings.RequiredApprovals,
+		settings.MaxOpenReviews,
+	).Scan(
+		&saved.TeamID,
+		&saved.ReviewerStrategy,
+		&saved.ReviewersPerPR,
+		&saved.MinReviewers,
+		&saved.FailOnInsufficient,
+		&saved.RequiredApprovals,
+		&saved.MaxOpenReviews,
+		&saved.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to save team settings: %w", err)
+	}
+
+	return &saved, nil
+}
+
+func (r *PgTeamRepository) GetFallbackTeams(ctx context.Context, teamID int) ([]entity.Team, error) {
+	query := `
+		SELECT t.id, t.name, t.created_at, t.updated_at
+		FROM team_fallbacks tf
+		JOIN teams t ON t.id = tf.fallback_team_id
+		WHERE tf.team_id = $1
+		ORDER BY tf.priority, t.id
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fallback teams: %w", err)
+	}
+	defer rows.Close()
+
+	var teams []entity.Team
+	for rows.Next() {
+		team := entity.Team{}
+		err := rows.Scan(
+			&team.ID,
+			&team.Name,
+			&team.CreatedAt,
+			&team.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan fallback team: %w", err)
+		}
+		teams = append(teams, team)
+	}
+
+	return teams, nil
+}
+
+func (r *PgTeamRepository) SetFallbackTeams(ctx context.Context, teamID int, fallbackTeamIDs []int) error {
+	query := `
+		DELETE FROM team_fallbacks
+		WHERE team_id = $1
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, teamID); err != nil {
+		return fmt.Errorf("failed to clear fallback teams: %w", err)
+	}
+
+	query = `
+		INSERT INTO team_fallbacks (team_id, fallback_team_id, priority)
+		VALUES ($1, $2, $3)
+	`
+
+	for priority, fallbackTeamID := range fallbackTeamIDs {
+		if _, err := r.conn(ctx).Exec(ctx, query, teamID, fallbackTeamID, priority); err != nil {
+			return fmt.Errorf("failed to add fallback team: %w", err)
+		}
+	}
+
+	return nil
+}
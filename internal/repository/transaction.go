@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DBTX - общий интерфейс пула соединений и транзакции pgx.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type txKey struct{}
+
+// PgUnitOfWork выполняет вызовы репозиториев в одной транзакции.
+// Транзакция передается через контекст, поэтому репозитории не нужно пересоздавать.
+type PgUnitOfWork struct {
+	db *pgxpool.Pool
+}
+
+func NewPgUnitOfWork(db *pgxpool.Pool) *PgUnitOfWork {
+	return &PgUnitOfWork{db: db}
+}
+
+// Do открывает транзакцию, выполняет fn и фиксирует изменения, если fn не вернула ошибку.
+// Вложенный вызов переиспользует уже открытую транзакцию.
+func (u *PgUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := u.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// executor возвращает транзакцию из контекста, а вне транзакции - пул.
+func executor(ctx context.Context, db *pgxpool.Pool) DBTX {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return db
+}
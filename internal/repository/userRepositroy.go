@@ -10,15 +10,19 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-type UserRepository struct {
+type PgUserRepository struct {
 	db *pgxpool.Pool
 }
 
-func NewUserRepository(db *pgxpool.Pool) *UserRepository {
-	return &UserRepository{db: db}
+func NewPgUserRepository(db *pgxpool.Pool) *PgUserRepository {
+	return &PgUserRepository{db: db}
 }
 
-func (r *UserRepository) Create(ctx context.Context, username string, isActive bool) (*entity.User, error) {
+func (r *PgUserRepository) conn(ctx context.Context) DBTX {
+	return executor(ctx, r.db)
+}
+
+func (r *PgUserRepository) Create(ctx context.Context, username string, isActive bool) (*entity.User, error) {
 	query := `
 		INSERT INTO users (username, is_active)
 		VALUES ($1, $2)
@@ -26,7 +30,7 @@ func (r *UserRepository) Create(ctx context.Context, username string, isActive b
 	`
 
 	user := entity.User{}
-	err := r.db.QueryRow(ctx, query, username, isActive).Scan(
+	err := r.conn(ctx).QueryRow(ctx, query, username, isActive).Scan(
 		&user.ID,
 		&user.Username,
 		&user.IsActive,
@@ -41,16 +45,18 @@ func (r *UserRepository) Create(ctx context.Context, username string, isActive b
 	return &user, nil
 }
 
-func (r *UserRepository) GetByID(ctx context.Context, userID int) (*entity.UserResponse, error) {
+func (r *PgUserRepository) GetByID(ctx context.Context, userID int) (*entity.UserResponse, error) {
 	query := `
 		SELECT users.id, username, is_active, teams.name FROM users
 		JOIN team_members on team_members.user_id = users.id
 		JOIN teams on teams.id = team_members.team_id
 		WHERE users.id = $1
+		ORDER BY team_members.is_primary DESC, team_members.team_id
+		LIMIT 1
 	`
 
 	user := entity.UserResponse{}
-	err := r.db.QueryRow(ctx, query, userID).Scan(
+	err := r.conn(ctx).QueryRow(ctx, query, userID).Scan(
 		&user.UserID,
 		&user.Username,
 		&user.IsActive,
@@ -67,7 +73,7 @@ func (r *UserRepository) GetByID(ctx context.Context, userID int) (*entity.UserR
 	return &user, nil
 }
 
-func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
+func (r *PgUserRepository) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
 	query := `
 		SELECT id, username, is_active, created_at, updated_at
 		FROM users
@@ -75,7 +81,7 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*e
 	`
 
 	user := entity.User{}
-	err := r.db.QueryRow(ctx, query, username).Scan(
+	err := r.conn(ctx).QueryRow(ctx, query, username).Scan(
 		&user.ID,
 		&user.Username,
 		&user.IsActive,
@@ -93,14 +99,16 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*e
 	return &user, nil
 }
 
-func (r *UserRepository) UpdateStatus(ctx context.Context, userID int, isActive bool) (*entity.UserResponse, error) {
+func (r *PgUserRepository) UpdateStatus(ctx context.Context, userID int, isActive bool) (*entity.UserResponse, error) {
 	query := `
 		UPDATE users
 		SET is_active = $1, updated_at = CURRENT_TIMESTAMP
 		WHERE id = $2
 	`
 
-	r.db.QueryRow(ctx, query, isActive, userID)
+	if _, err := r.conn(ctx).Exec(ctx, query, isActive, userID); err != nil {
+		return nil, fmt.Errorf("failed to update user status: %w", err)
+	}
 
 	query = `
 		SELECT users.id, username, teams.name, is_active FROM users
@@ -110,7 +118,7 @@ func (r *UserRepository) UpdateStatus(ctx context.Context, userID int, isActive
 	`
 
 	user := entity.UserResponse{}
-	err := r.db.QueryRow(ctx, query, userID).Scan(
+	err := r.conn(ctx).QueryRow(ctx, query, userID).Scan(
 		&user.UserID,
 		&user.Username,
 		&user.TeamName,
@@ -123,7 +131,7 @@ func (r *UserRepository) UpdateStatus(ctx context.Context, userID int, isActive
 	return &user, nil
 }
 
-func (r *UserRepository) Upsert(ctx context.Context, username string, isActive bool) (*entity.UserResponse, error) {
+func (r *PgUserRepository) Upsert(ctx context.Context, username string, isActive bool) (*entity.UserResponse, error) {
 	query := `
 		INSERT INTO users (username, is_active)
 		VALUES ($1, $2)
@@ -132,21 +140,24 @@ func (r *UserRepository) Upsert(ctx context.Context, username string, isActive b
 		RETURNING id
 	`
 	userID := 0
-	err := r.db.QueryRow(ctx, query, username, isActive).Scan(
+	err := r.conn(ctx).QueryRow(ctx, query, username, isActive).Scan(
 		&userID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to upsert user: %w", err)
 	}
 
+	// Новый пользователь еще не состоит в команде - имя команды пустое
 	query = `
-		SELECT users.id, username, is_active, teams.name FROM users
-		JOIN team_members on team_members.user_id = users.id
-		JOIN teams on teams.id = team_members.team_id
+		SELECT users.id, username, is_active, COALESCE(teams.name, '') FROM users
+		LEFT JOIN team_members on team_members.user_id = users.id
+		LEFT JOIN teams on teams.id = team_members.team_id
 		WHERE users.id = $1
+		ORDER BY team_members.is_primary DESC, team_members.team_id
+		LIMIT 1
 	`
 	user := entity.UserResponse{}
-	err = r.db.QueryRow(ctx, query, userID).Scan(
+	err = r.conn(ctx).QueryRow(ctx, query, userID).Scan(
 		&user.UserID,
 		&user.Username,
 		&user.IsActive,
@@ -160,14 +171,15 @@ func (r *UserRepository) Upsert(ctx context.Context, username string, isActive b
 	return &user, nil
 }
 
-func (r *UserRepository) GetTeamsByUserID(ctx context.Context, userID int) ([]int, error) {
+func (r *PgUserRepository) GetTeamsByUserID(ctx context.Context, userID int) ([]int, error) {
 	query := `
 		SELECT team_id
 		FROM team_members
 		WHERE user_id = $1
+		ORDER BY is_primary DESC, team_id
 	`
 
-	rows, err := r.db.Query(ctx, query, userID)
+	rows, err := r.conn(ctx).Query(ctx, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query user teams: %w", err)
 	}
@@ -184,3 +196,36 @@ func (r *UserRepository) GetTeamsByUserID(ctx context.Context, userID int) ([]in
 
 	return teamIDs, nil
 }
+
+func (r *PgUserRepository) SetActiveMany(ctx context.Context, userIDs []int, isActive bool) ([]entity.User, error) {
+	query := `
+		UPDATE users
+		SET is_active = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ANY($1)
+		RETURNING id, username, is_active, created_at, updated_at
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, userIDs, isActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update users status: %w", err)
+	}
+	defer rows.Close()
+
+	var users []entity.User
+	for rows.Next() {
+		user := entity.User{}
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.IsActive,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
@@ -0,0 +1,64 @@
+package router_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"PR-appointer/config"
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+	"PR-appointer/internal/router"
+	"PR-appointer/internal/service"
+	"PR-appointer/internal/storage/storagetest"
+)
+
+// newAuthAPI поднимает роутер с включенной аутентификацией и токеном роли role для пользователя username.
+func newAuthAPI(t *testing.T, store *repository.Store, role, username string) *testAPI {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	token, err := service.NewAuthService(store, nil).IssueToken(ctx, "test", role, username)
+	if err != nil {
+		t.Fatalf("issue %s token: %v", role, err)
+	}
+
+	engine, err := router.SetupRouter(ctx, &config.Config{Env: config.Env{AuthEnabled: true}}, store)
+	if err != nil {
+		t.Fatalf("setup router: %v", err)
+	}
+
+	return &testAPI{t: t, engine: engine, token: token.Token}
+}
+
+func TestMemberPermissions(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		// Команду и PR автора user1 создаем без аутентификации
+		setupPR(newTestAPI(t, store), 1)
+
+		member := newAuthAPI(t, store, entity.RoleMember, "user2")
+		resp := member.do(http.MethodPost, "/team/add", map[string]any{
+			"team_name": "frontend",
+			"members":   []map[string]any{{"username": "user6", "is_active": true}},
+		})
+		if resp.status != http.StatusForbidden || errorCode(resp.body) != "FORBIDDEN" {
+			t.Errorf("member /team/add: status %d, want 403 FORBIDDEN: %v", resp.status, resp.body)
+		}
+
+		resp = member.do(http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": 1})
+		if resp.status != http.StatusForbidden || errorCode(resp.body) != "FORBIDDEN" {
+			t.Errorf("member merge of another author's PR: status %d, want 403 FORBIDDEN: %v", resp.status, resp.body)
+		}
+
+		// Несуществующий PR по-прежнему дает 404, а автор может слить свой PR
+		resp = member.do(http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": 2})
+		if resp.status != http.StatusNotFound {
+			t.Errorf("member merge of missing PR: status %d, want 404: %v", resp.status, resp.body)
+		}
+		author := newAuthAPI(t, store, entity.RoleMember, "user1")
+		author.must(http.StatusOK, http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": 1})
+	})
+}
@@ -1,18 +1,21 @@
 package router
 
 import (
+	"PR-appointer/config"
+	"PR-appointer/internal/entity"
 	"PR-appointer/internal/handler"
 	"PR-appointer/internal/middleware"
+	"PR-appointer/internal/repository"
+	"PR-appointer/internal/service"
 	"context"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgxpool"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRouter(ctx context.Context, db *pgxpool.Pool) *gin.Engine {
+func SetupRouter(ctx context.Context, cfg *config.Config, store *repository.Store) (*gin.Engine, error) {
 	router := gin.Default()
 
 	corsConfig := cors.DefaultConfig()
@@ -29,31 +32,91 @@ func SetupRouter(ctx context.Context, db *pgxpool.Pool) *gin.Engine {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
-	teamHandler := handler.NewTeamHandler(ctx, db)
-	userHandler := handler.NewUserHandler(ctx, db)
-	PRHandler := handler.NewPRHandler(ctx, db)
+	// Один источник зерен на все сервисы: при заданном ASSIGNMENT_SEED последовательность выборов воспроизводится
+	seeds := service.NewSeedSource(service.SeedConfig{
+		Seed:  cfg.Env.AssignmentSeed,
+		PerPR: cfg.Env.AssignmentSeedPerPR,
+	})
+
+	teamHandler := handler.NewTeamHandler(ctx, store)
+	userHandler := handler.NewUserHandler(ctx, store, seeds)
+	PRHandler := handler.NewPRHandler(ctx, store, seeds)
+	subscriptionHandler := handler.NewSubscriptionHandler(ctx, store)
+	auditHandler := handler.NewAuditHandler(ctx, store)
+	webhookHandler := handler.NewWebhookHandler(ctx, store, handler.WebhookSecrets{
+		GitHub: cfg.Env.GitHubWebhookSecret,
+		GitLab: cfg.Env.GitLabWebhookToken,
+	}, seeds)
 
-	api := router.Group("/")
+	// Вебхуки подписываются секретом провайдера, токены API для них не нужны
+	webhooks := router.Group("/webhooks")
+	{
+		webhooks.POST("/github", webhookHandler.GitHub)
+		webhooks.POST("/gitlab", webhookHandler.GitLab)
+	}
+
+	// Права на действия с конкретным PR (автор, ревьювер) проверяют обработчики
+	var jwtVerifier *service.JWTVerifier
+	if cfg.Env.JWTKeysFile != "" {
+		var err error
+		jwtVerifier, err = service.NewJWTVerifier(service.JWTConfig{
+			KeysFile:      cfg.Env.JWTKeysFile,
+			UsernameClaim: cfg.Env.JWTUsernameClaim,
+			RoleClaim:     cfg.Env.JWTRoleClaim,
+			Issuer:        cfg.Env.JWTIssuer,
+			Audience:      cfg.Env.JWTAudience,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	authService := service.NewAuthService(store, jwtVerifier)
+	admins := middleware.RequireRole(entity.RoleAdmin)
+	leads := middleware.RequireRole(entity.RoleAdmin, entity.RoleTeamLead)
+
+	api := router.Group("/", middleware.Authenticate(authService, cfg.Env.AuthEnabled))
 	{
 		teams := api.Group("/team")
 		{
-			teams.POST("/add", teamHandler.AddTeam)
+			teams.POST("/add", admins, teamHandler.AddTeam)
 			teams.GET("/get", teamHandler.GetTeam)
+			teams.GET("/settings", teamHandler.GetSettings)
+			teams.POST("/settings", leads, teamHandler.UpdateSettings)
 		}
 
 		users := api.Group("/users")
 		{
-			users.POST("/setIsActive", userHandler.SetStatus)
+			users.POST("/setIsActive", leads, userHandler.SetStatus)
+			users.POST("/bulkDeactivate", leads, userHandler.BulkDeactivate)
 			users.GET("/getReview", userHandler.GetUserReviews)
 		}
 
 		PRs := api.Group("/pullRequest")
 		{
 			PRs.POST("/create", PRHandler.CreatePR)
+			PRs.POST("/preview", PRHandler.PreviewPR)
 			PRs.POST("/merge", PRHandler.MergePR)
+			PRs.POST("/ready", PRHandler.MarkReady)
+			PRs.POST("/close", PRHandler.ClosePR)
+			PRs.POST("/reopen", PRHandler.ReopenPR)
 			PRs.POST("/reassign", PRHandler.ReassignReviewer)
+			PRs.POST("/review", PRHandler.SubmitReview)
+			PRs.GET("/get", PRHandler.GetPR)
+			PRs.GET("/list", PRHandler.ListPRs)
+		}
+
+		api.GET("/audit", leads, auditHandler.GetAudit)
+
+		subscriptions := api.Group("/subscriptions", admins)
+		{
+			subscriptions.POST("/add", subscriptionHandler.AddSubscription)
+			subscriptions.GET("/list", subscriptionHandler.ListSubscriptions)
+			subscriptions.POST("/remove", subscriptionHandler.RemoveSubscription)
+			subscriptions.GET("/deliveries", subscriptionHandler.ListDeliveries)
+			subscriptions.POST("/redeliver", subscriptionHandler.Redeliver)
 		}
 	}
 
-	return router
+	return router, nil
 }
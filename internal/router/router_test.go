@@ -0,0 +1,299 @@
+package router_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"PR-appointer/config"
+	"PR-appointer/internal/repository"
+	"PR-appointer/internal/router"
+	"PR-appointer/internal/storage/storagetest"
+)
+
+// parallel - сколько одинаковых запросов отправляется одновременно
+const parallel = 10
+
+type response struct {
+	status int
+	body   map[string]any
+}
+
+type testAPI struct {
+	t      *testing.T
+	engine *gin.Engine
+	// token передается в Authorization, если задан
+	token string
+}
+
+func newTestAPI(t *testing.T, store *repository.Store) *testAPI {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	engine, err := router.SetupRouter(context.Background(), &config.Config{}, store)
+	if err != nil {
+		t.Fatalf("setup router: %v", err)
+	}
+
+	return &testAPI{t: t, engine: engine}
+}
+
+func (a *testAPI) do(method, path string, body any) response {
+	var reader *bytes.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			a.t.Errorf("marshal body: %v", err)
+		}
+		reader = bytes.NewReader(raw)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if a.token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	}
+	rec := httptest.NewRecorder()
+	a.engine.ServeHTTP(rec, req)
+
+	resp := response{status: rec.Code}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp.body); err != nil {
+		a.t.Errorf("%s %s: decode response %q: %v", method, path, rec.Body.String(), err)
+	}
+	return resp
+}
+
+// must выполняет запрос, который должен завершиться статусом want.
+func (a *testAPI) must(want int, method, path string, body any) map[string]any {
+	a.t.Helper()
+
+	resp := a.do(method, path, body)
+	if resp.status != want {
+		a.t.Fatalf("%s %s: status %d, want %d: %v", method, path, resp.status, want, resp.body)
+	}
+	return resp.body
+}
+
+// race отправляет parallel одинаковых запросов одновременно.
+func (a *testAPI) race(method, path string, body any) []response {
+	responses := make([]response, parallel)
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := range responses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			responses[i] = a.do(method, path, body)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	return responses
+}
+
+// reviewerCount считает, у скольких из userIDs PR prID есть в списке на ревью.
+func (a *testAPI) reviewerCount(userIDs []int, prID int) int {
+	a.t.Helper()
+
+	count := 0
+	for _, userID := range userIDs {
+		reviews := a.must(http.StatusOK, http.MethodGet, "/users/getReview?user_id="+strconv.Itoa(userID), nil)
+		prs, _ := reviews["pull_requests"].([]any)
+		for _, pr := range prs {
+			if int(pr.(map[string]any)["pull_request_id"].(float64)) == prID {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// expectOneWinner проверяет, что ровно один запрос выполнен со статусом success,
+// а остальные получили 409 с кодом code.
+func expectOneWinner(t *testing.T, responses []response, success int, code string) {
+	t.Helper()
+
+	winners := 0
+	for _, resp := range responses {
+		switch {
+		case resp.status == success:
+			winners++
+		case resp.status == http.StatusConflict && errorCode(resp.body) == code:
+		default:
+			t.Errorf("unexpected response %d: %v", resp.status, resp.body)
+		}
+	}
+	if winners != 1 {
+		t.Errorf("%d requests succeeded, want exactly 1", winners)
+	}
+}
+
+func errorCode(body map[string]any) string {
+	apiErr, _ := body["error"].(map[string]any)
+	code, _ := apiErr["code"].(string)
+	return code
+}
+
+// setupPR создает команду из пяти человек и открытый PR первого из них с двумя ревьюверами.
+// Возвращает id всех участников (автор первый) и назначенных ревьюверов.
+func setupPR(api *testAPI, prID int) (memberIDs, reviewerIDs []int) {
+	api.t.Helper()
+
+	members := []map[string]any{}
+	for i := 1; i <= 5; i++ {
+		members = append(members, map[string]any{"username": fmt.Sprintf("user%d", i), "is_active": true})
+	}
+	team := api.must(http.StatusCreated, http.MethodPost, "/team/add", map[string]any{
+		"team_name": "backend",
+		"members":   members,
+	})
+	for _, m := range team["team"].(map[string]any)["members"].([]any) {
+		memberIDs = append(memberIDs, int(m.(map[string]any)["user_id"].(float64)))
+	}
+
+	pr := api.must(http.StatusCreated, http.MethodPost, "/pullRequest/create", map[string]any{
+		"pull_request_id":   prID,
+		"pull_request_name": "Add feature",
+		"author_id":         memberIDs[0],
+	})
+	for _, r := range pr["pr"].(map[string]any)["reviewers"].([]any) {
+		reviewerIDs = append(reviewerIDs, int(r.(map[string]any)["user_id"].(float64)))
+	}
+	if len(reviewerIDs) != 2 {
+		api.t.Fatalf("PR has %d reviewers, want 2", len(reviewerIDs))
+	}
+
+	return memberIDs, reviewerIDs
+}
+
+func TestConcurrentReassign(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		api := newTestAPI(t, store)
+		memberIDs, reviewerIDs := setupPR(api, 1)
+
+		responses := api.race(http.MethodPost, "/pullRequest/reassign", map[string]any{
+			"pull_request_id": 1,
+			"old_reviewer_id": reviewerIDs[0],
+		})
+		expectOneWinner(t, responses, http.StatusOK, "NOT_ASSIGNED")
+
+		if count := api.reviewerCount(memberIDs, 1); count != 2 {
+			t.Errorf("PR has %d reviewers after reassign, want 2", count)
+		}
+	})
+}
+
+func TestConcurrentCreate(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		api := newTestAPI(t, store)
+		memberIDs, _ := setupPR(api, 1)
+
+		responses := api.race(http.MethodPost, "/pullRequest/create", map[string]any{
+			"pull_request_id":   2,
+			"pull_request_name": "Same PR",
+			"author_id":         memberIDs[0],
+		})
+		expectOneWinner(t, responses, http.StatusCreated, "PR_EXISTS")
+	})
+}
+
+func TestConcurrentClose(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		api := newTestAPI(t, store)
+		setupPR(api, 1)
+
+		responses := api.race(http.MethodPost, "/pullRequest/close", map[string]any{"pull_request_id": 1})
+		expectOneWinner(t, responses, http.StatusOK, "INVALID_TRANSITION")
+	})
+}
+
+func TestConcurrentMerge(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		api := newTestAPI(t, store)
+		setupPR(api, 1)
+
+		// merge идемпотентен: все запросы успешны, но статус меняется один раз - у всех одно время merge
+		responses := api.race(http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": 1})
+		var mergedAt any
+		for i, resp := range responses {
+			if resp.status != http.StatusOK {
+				t.Fatalf("merge: status %d, want 200: %v", resp.status, resp.body)
+			}
+			got := resp.body["pr"].(map[string]any)["merged_at"]
+			if i == 0 {
+				mergedAt = got
+			} else if !reflect.DeepEqual(got, mergedAt) {
+				t.Errorf("merged_at %v, want %v as in the first response", got, mergedAt)
+			}
+		}
+
+		audit := api.must(http.StatusOK, http.MethodGet, "/audit?pull_request_id=1", nil)
+		merges := 0
+		for _, e := range audit["entries"].([]any) {
+			entry := e.(map[string]any)
+			after, _ := entry["after"].(map[string]any)
+			if entry["action"] == "pr.status_changed" && after["status"] == "MERGED" {
+				merges++
+			}
+		}
+		if merges != 1 {
+			t.Errorf("%d merge status changes in audit log, want 1", merges)
+		}
+	})
+}
+
+func TestConcurrentReassignAndMerge(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		api := newTestAPI(t, store)
+		_, reviewerIDs := setupPR(api, 1)
+
+		// Переназначение, начатое после merge, получает 409, а не ошибку ограничения
+		var wg sync.WaitGroup
+		var merge response
+		var reassigns []response
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			merge = api.do(http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": 1})
+		}()
+		go func() {
+			defer wg.Done()
+			reassigns = api.race(http.MethodPost, "/pullRequest/reassign", map[string]any{
+				"pull_request_id": 1,
+				"old_reviewer_id": reviewerIDs[0],
+			})
+		}()
+		wg.Wait()
+
+		if merge.status != http.StatusOK {
+			t.Fatalf("merge: status %d, want 200: %v", merge.status, merge.body)
+		}
+		succeeded := 0
+		for _, resp := range reassigns {
+			switch {
+			case resp.status == http.StatusOK:
+				succeeded++
+			case resp.status == http.StatusConflict && (errorCode(resp.body) == "NOT_ASSIGNED" || errorCode(resp.body) == "PR_MERGED"):
+			default:
+				t.Errorf("unexpected reassign response %d: %v", resp.status, resp.body)
+			}
+		}
+		if succeeded > 1 {
+			t.Errorf("%d reassigns succeeded, want at most 1", succeeded)
+		}
+	})
+}
@@ -6,69 +6,99 @@ import (
 	"fmt"
 	"log/slog"
 	"math/rand"
+	"slices"
 	"strconv"
 
-	"github.com/jackc/pgx/v5/pgxpool"
-
 	"PR-appointer/internal/entity"
 	"PR-appointer/internal/repository"
 )
 
 type PRService struct {
-	prRepo   *repository.PRRepository
-	userRepo *repository.UserRepository
-	teamRepo *repository.TeamRepository
+	prRepo    repository.PRRepository
+	userRepo  repository.UserRepository
+	teamRepo  repository.TeamRepository
+	uow       repository.UnitOfWork
+	selectors map[string]ReviewerSelector
+	seeds     *SeedSource
+	events    EventPublisher
+	audit     *auditLog
 }
 
-func NewPRService(db *pgxpool.Pool) *PRService {
+func NewPRService(store *repository.Store, seeds *SeedSource) *PRService {
 	return &PRService{
-		prRepo:   repository.NewPRRepository(db),
-		userRepo: repository.NewUserRepository(db),
-		teamRepo: repository.NewTeamRepository(db),
+		prRepo:    store.PRs,
+		userRepo:  store.Users,
+		teamRepo:  store.Teams,
+		uow:       store.UoW,
+		selectors: newReviewerSelectors(store.PRs, store.Teams),
+		seeds:     seeds,
+		events:    newOutboxPublisher(store),
+		audit:     newAuditLog(store),
 	}
 }
 
 func (s *PRService) CreatePR(ctx context.Context, req *entity.PRCreateRequest) (*entity.PRDetailResponse, error) {
-	author, err := s.userRepo.GetByID(ctx, req.AuthorID)
-	if err != nil {
-		return nil, errors.New("author not found")
-	}
+	// PR и его ревьюверы создаются атомарно
+	return inTx(ctx, s.uow, func(ctx context.Context) (*entity.PRDetailResponse, error) {
+		return s.createPR(ctx, req)
+	})
+}
 
-	// Получаем команды автора
-	teamIDs, err := s.userRepo.GetTeamsByUserID(ctx, req.AuthorID)
+func (s *PRService) createPR(ctx context.Context, req *entity.PRCreateRequest) (*entity.PRDetailResponse, error) {
+	author, teamIDs, err := s.authorTeams(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(teamIDs) == 0 {
-		return nil, errors.New("author is not in any team")
+	// Команда PR - указанная явно, основная команда автора или первая по id.
+	// Кандидаты объединяются по всем командам автора, настройки берутся из команды PR
+	teamID := teamIDs[0]
+
+	status := entity.StatusOpen
+	if req.Draft {
+		status = entity.StatusDraft
 	}
 
-	pr, err := s.prRepo.Create(ctx, req.PullRequestID, req.PullRequestName, req.AuthorID)
+	// PR создается до выбора ревьюверов: без своего id (из вебхука) он получает id от хранилища,
+	// а выбор по id PR должен видеть уже выданный id. При ошибке выбора транзакция откатывается
+	pr, err := s.prRepo.Create(ctx, req.PullRequestID, req.PullRequestName, req.AuthorID, teamID, status, false)
 	if err != nil {
-		fmt.Println(err.Error())
-		return nil, errors.New("PR already exists")
+		slog.Error("Error creating PR", "pr_id", req.PullRequestID, "err", err)
+		return nil, err
 	}
 
-	// Назначаем ревьюверов из первой команды автора
-	teamID := teamIDs[0]
-	reviewers, err := s.assignReviewers(ctx, pr.ID, teamID, req.AuthorID)
+	// Черновику ревьюверы не назначаются до перевода в OPEN
+	var selected []entity.ReviewerResponse
+	var explain *entity.AssignmentExplanation
+	if !req.Draft {
+		selected, explain, err = s.pickReviewers(ctx, pr.ID, teamIDs, req.AuthorID)
+		if err != nil {
+			return nil, err
+		}
+		if explain.Degraded {
+			if err := s.prRepo.SetDegraded(ctx, pr.ID, true); err != nil {
+				return nil, err
+			}
+			pr.Degraded = true
+		}
+	}
+
+	err = s.audit.record(ctx, entity.AuditEntry{
+		Action:        entity.AuditPRCreated,
+		PullRequestID: pr.ID,
+		UserID:        pr.AuthorID,
+		TeamID:        teamID,
+	}, nil, prState(pr))
 	if err != nil {
-		reviewers = []entity.UserResponse{}
+		return nil, err
 	}
 
-	// Формируем ответ
-	var reviewerResponses []entity.UserResponse
-	for _, reviewer := range reviewers {
-		reviewerResponses = append(reviewerResponses, entity.UserResponse{
-			UserID:   reviewer.UserID,
-			Username: reviewer.Username,
-			IsActive: reviewer.IsActive,
-			TeamName: reviewer.TeamName,
-		})
+	reviewerResponses, err := s.assignReviewers(ctx, pr.ID, selected, explain)
+	if err != nil {
+		return nil, err
 	}
 
-	return &entity.PRDetailResponse{
+	created := &entity.PRDetailResponse{
 		PullRequestID:   pr.ID,
 		PullRequestName: pr.Title,
 		Author: entity.UserResponse{
@@ -79,58 +109,571 @@ func (s *PRService) CreatePR(ctx context.Context, req *entity.PRCreateRequest) (
 		},
 		Status:    string(pr.Status),
 		Reviewers: reviewerResponses,
+		Degraded:  pr.Degraded,
+	}
+
+	if err = s.events.Publish(ctx, entity.EventPRCreated, created); err != nil {
+		return nil, err
+	}
+
+	// Объяснение нужно только в ответе, в событие оно не попадает
+	created.Explain = explain
+	return created, nil
+}
+
+// PreviewPR выбирает ревьюверов так же, как CreatePR, но ничего не сохраняет.
+func (s *PRService) PreviewPR(ctx context.Context, req *entity.PRCreateRequest) (*entity.PRPreviewResponse, error) {
+	author, teamIDs, err := s.authorTeams(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, explain, err := s.pickReviewers(ctx, req.PullRequestID, teamIDs, req.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if selected == nil {
+		selected = []entity.ReviewerResponse{}
+	}
+
+	return &entity.PRPreviewResponse{
+		Author:    *author,
+		Reviewers: selected,
+		Degraded:  explain.Degraded,
+		Explain:   explain,
 	}, nil
 }
 
-func (s *PRService) assignReviewers(ctx context.Context, prID, teamID, authorID int) ([]entity.UserResponse, error) {
-	candidates, err := s.teamRepo.GetActiveMembers(ctx, teamID, &authorID)
+// authorTeams находит автора и команды, из которых выбираются ревьюверы его PR.
+func (s *PRService) authorTeams(ctx context.Context, req *entity.PRCreateRequest) (*entity.UserResponse, []int, error) {
+	author, err := s.userRepo.GetByID(ctx, req.AuthorID)
+	if err != nil {
+		return nil, nil, errors.New("author not found")
+	}
+
+	// Получаем команды автора
+	teamIDs, err := s.userRepo.GetTeamsByUserID(ctx, req.AuthorID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(teamIDs) == 0 {
+		return nil, nil, errors.New("author is not in any team")
+	}
+
+	// Явно указанная команда ограничивает пул кандидатов только ею
+	if req.TeamName != "" {
+		team, err := s.teamRepo.GetByName(ctx, req.TeamName)
+		if err != nil {
+			return nil, nil, errors.New("team not found")
+		}
+		if !slices.Contains(teamIDs, team.ID) {
+			return nil, nil, errors.New("author is not a member of team")
+		}
+		teamIDs = []int{team.ID}
+	}
+
+	return author, teamIDs, nil
+}
+
+// pickReviewers выбирает ревьюверов для PR по настройкам команды teamIDs[0].
+// В объяснении Degraded сообщает, меньше ли их, чем минимум команды.
+func (s *PRService) pickReviewers(ctx context.Context, prID int, teamIDs []int, authorID int) ([]entity.ReviewerResponse, *entity.AssignmentExplanation, error) {
+	settings, err := s.teamRepo.GetSettings(ctx, teamIDs[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	explain := s.newExplanation(settings, prID)
+	excludeIDs := map[int]string{authorID: entity.ExcludedAuthor}
+	selected, err := s.selectReviewers(ctx, teamIDs, settings, excludeIDs, settings.ReviewersPerPR, explain)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Меньше минимума: либо отказ, либо PR в деградированном состоянии
+	explain.Degraded = len(selected) < settings.MinReviewers
+	if explain.Degraded && settings.FailOnInsufficient {
+		return nil, nil, &SelectionError{Message: "not enough active reviewers in team", Explain: explain}
+	}
+
+	return selected, explain, nil
+}
+
+// selectReviewers выбирает до n ревьюверов из объединенного пула команд teamIDs,
+// а при нехватке кандидатов добирает недостающих из резервных команд первой из них.
+// excludeIDs - кто не может быть выбран и почему; каждый пул кандидатов добавляется в explain.
+func (s *PRService) selectReviewers(ctx context.Context, teamIDs []int, settings *entity.TeamSettings, excludeIDs map[int]string, n int, explain *entity.AssignmentExplanation) ([]entity.ReviewerResponse, error) {
+	selected, err := s.selectFromTeams(ctx, teamIDs, settings, excludeIDs, n, false, explain)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(selected) >= n {
+		return selected, nil
+	}
+
+	fallbackTeams, err := s.teamRepo.GetFallbackTeams(ctx, teamIDs[0])
 	if err != nil {
 		return nil, err
 	}
 
-	// Выбираем до 2 случайных ревьюверов
-	numReviewers := min(2, len(candidates))
+	for _, fallbackTeam := range fallbackTeams {
+		if len(selected) >= n {
+			break
+		}
+
+		for _, reviewer := range selected {
+			excludeIDs[reviewer.UserID] = entity.ExcludedAlreadySelected
+		}
 
-	// Перемешиваем кандидатов
-	rand.Shuffle(len(candidates), func(i, j int) {
-		candidates[i], candidates[j] = candidates[j], candidates[i]
-	})
+		fallbackSettings, err := s.teamRepo.GetSettings(ctx, fallbackTeam.ID)
+		if err != nil {
+			return nil, err
+		}
 
-	var assignedReviewers []entity.UserResponse
-	for i := 0; i < numReviewers; i++ {
-		reviewer := candidates[i]
-		if err := s.prRepo.AddReviewer(ctx, prID, reviewer.UserID); err != nil {
-			continue
+		more, err := s.selectFromTeams(ctx, []int{fallbackTeam.ID}, fallbackSettings, excludeIDs, n-len(selected), true, explain)
+		if err != nil {
+			return nil, err
 		}
-		assignedReviewers = append(assignedReviewers, reviewer)
+		selected = append(selected, more...)
 	}
 
-	return assignedReviewers, nil
+	return selected, nil
 }
 
-func (s *PRService) MergePR(ctx context.Context, prID int) (*entity.MergedPRResponse, error) {
+func (s *PRService) selectFromTeams(ctx context.Context, teamIDs []int, settings *entity.TeamSettings, excludeIDs map[int]string, n int, crossTeam bool, explain *entity.AssignmentExplanation) ([]entity.ReviewerResponse, error) {
+	pool := entity.CandidatePool{
+		Teams:      []string{},
+		Fallback:   crossTeam,
+		Strategy:   settings.ReviewerStrategy,
+		Needed:     n,
+		Candidates: []int{},
+		Excluded:   []entity.ExcludedCandidate{},
+		Selected:   []int{},
+	}
+
+	// Участник нескольких команд относится к первой из них, в которой найден
+	var candidates []entity.UserResponse
+	sourceTeams := make(map[int]int)
+	seen := make(map[int]bool)
+	for _, teamID := range teamIDs {
+		members, err := s.teamRepo.GetMembers(ctx, teamID)
+		if err != nil {
+			return nil, err
+		}
+		if len(members) > 0 {
+			pool.Teams = append(pool.Teams, members[0].TeamName)
+		}
+
+		for _, member := range members {
+			if seen[member.UserID] {
+				continue
+			}
+			seen[member.UserID] = true
+
+			reason, excluded := excludeIDs[member.UserID]
+			if !excluded && !member.IsActive {
+				reason, excluded = entity.ExcludedInactive, true
+			}
+			if excluded {
+				pool.Excluded = append(pool.Excluded, entity.ExcludedCandidate{
+					UserID:   member.UserID,
+					Username: member.Username,
+					Reason:   reason,
+				})
+				continue
+			}
+
+			sourceTeams[member.UserID] = teamID
+			candidates = append(candidates, member)
+		}
+	}
+
+	// Участник с открытыми ревью на пределе команды не становится кандидатом
+	if settings.MaxOpenReviews > 0 && len(candidates) > 0 {
+		ids := make([]int, 0, len(candidates))
+		for _, candidate := range candidates {
+			ids = append(ids, candidate.UserID)
+		}
+		openCounts, err := s.prRepo.GetOpenReviewCounts(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		available := candidates[:0]
+		for _, candidate := range candidates {
+			if openCounts[candidate.UserID] >= settings.MaxOpenReviews {
+				pool.Excluded = append(pool.Excluded, entity.ExcludedCandidate{
+					UserID:   candidate.UserID,
+					Username: candidate.Username,
+					Reason:   entity.ExcludedOverCapacity,
+				})
+				continue
+			}
+			available = append(available, candidate)
+		}
+		candidates = available
+	}
+	for _, candidate := range candidates {
+		pool.Candidates = append(pool.Candidates, candidate.UserID)
+	}
+
+	if len(candidates) == 0 {
+		explain.Pools = append(explain.Pools, pool)
+		return nil, nil
+	}
+
+	// Каждый пул выбирается генератором с зерном всего выбора: результат зависит только от зерна и кандидатов
+	rng := rand.New(rand.NewSource(explain.Seed))
+	picked, err := s.selectorFor(settings).Select(ctx, teamIDs[0], candidates, n, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := fmt.Sprintf("picked by %s strategy from %d candidates", settings.ReviewerStrategy, len(candidates))
+	if crossTeam {
+		reason += " of fallback team"
+	}
+
+	var selected []entity.ReviewerResponse
+	for _, user := range picked {
+		selected = append(selected, entity.ReviewerResponse{
+			UserID:    user.UserID,
+			Username:  user.Username,
+			TeamID:    sourceTeams[user.UserID],
+			TeamName:  user.TeamName,
+			IsActive:  user.IsActive,
+			CrossTeam: crossTeam,
+			Reason:    reason,
+		})
+		pool.Selected = append(pool.Selected, user.UserID)
+	}
+	explain.Pools = append(explain.Pools, pool)
+
+	return selected, nil
+}
+
+// assignReviewers назначает выбранных ревьюверов; explain - объяснение их выбора, из него в журнал пишется зерно.
+func (s *PRService) assignReviewers(ctx context.Context, prID int, selected []entity.ReviewerResponse, explain *entity.AssignmentExplanation) ([]entity.ReviewerResponse, error) {
+	for _, reviewer := range selected {
+		if err := s.prRepo.AddReviewer(ctx, prID, reviewer.UserID, reviewer.TeamID, reviewer.CrossTeam); err != nil {
+			slog.Error("Error adding reviewer", strconv.Itoa(prID), err.Error())
+			return nil, err
+		}
+
+		if err := s.recordAssigned(ctx, prID, reviewer, explain.Seed); err != nil {
+			return nil, err
+		}
+
+		event := entity.ReviewerAssignedEvent{PullRequestID: prID, Reviewer: reviewer}
+		if err := s.events.Publish(ctx, entity.EventReviewerAssigned, event); err != nil {
+			return nil, err
+		}
+	}
+
+	return selected, nil
+}
+
+func (s *PRService) selectorFor(settings *entity.TeamSettings) ReviewerSelector {
+	selector, ok := s.selectors[settings.ReviewerStrategy]
+	if !ok {
+		slog.Warn("Unknown reviewer strategy, falling back to random", "strategy", settings.ReviewerStrategy)
+		return s.selectors[entity.StrategyRandom]
+	}
+
+	return selector
+}
+
+// GetPR возвращает PR без ревьюверов, например чтобы проверить права на действие с ним.
+func (s *PRService) GetPR(ctx context.Context, prID int) (*entity.PullRequest, error) {
+	return s.prRepo.GetByID(ctx, prID)
+}
+
+// GetPRDetails возвращает PR с ревьюверами и временем их назначения.
+func (s *PRService) GetPRDetails(ctx context.Context, prID int) (*entity.PRDetailResponse, error) {
 	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getPRDetails(ctx, pr)
+}
+
+func (s *PRService) MergePR(ctx context.Context, prID int) (*entity.MergedPRResponse, error) {
+	return inTx(ctx, s.uow, func(ctx context.Context) (*entity.MergedPRResponse, error) {
+		return s.mergePR(ctx, prID, true)
+	})
+}
+
+// RecordMerge фиксирует слияние, уже выполненное во внешней системе:
+// число одобрений не проверяется, иначе статус PR разойдется с реальным.
+func (s *PRService) RecordMerge(ctx context.Context, prID int) (*entity.MergedPRResponse, error) {
+	return inTx(ctx, s.uow, func(ctx context.Context) (*entity.MergedPRResponse, error) {
+		return s.mergePR(ctx, prID, false)
+	})
+}
+
+func (s *PRService) mergePR(ctx context.Context, prID int, checkApprovals bool) (*entity.MergedPRResponse, error) {
+	pr, err := s.prRepo.GetByIDForUpdate(ctx, prID)
 	if err != nil {
 		slog.Error("Error getting PR", strconv.Itoa(prID), err)
 		return nil, err
 	}
 
 	// если уже MERGED, просто возвращаем
-	if pr.Status == "MERGED" {
+	if pr.Status == entity.StatusMerged {
 		return s.getMergedPRDetails(ctx, pr)
 	}
 
+	if err = checkTransition(pr.Status, entity.StatusMerged); err != nil {
+		return nil, err
+	}
+
+	// Проверяем, набрано ли нужное командой число одобрений
+	if checkApprovals && pr.TeamID != nil {
+		settings, err := s.teamRepo.GetSettings(ctx, *pr.TeamID)
+		if err != nil {
+			return nil, err
+		}
+
+		if settings.RequiredApprovals > 0 {
+			approvals, err := s.prRepo.CountApprovals(ctx, pr.ID)
+			if err != nil {
+				return nil, err
+			}
+			if approvals < settings.RequiredApprovals {
+				return nil, errors.New("not enough approvals to merge")
+			}
+		}
+	}
+
 	// Обновляем статус
-	pr, err = s.prRepo.UpdateStatus(ctx, prID, "MERGED")
+	before := prState(pr)
+	pr, err = s.prRepo.UpdateStatus(ctx, prID, entity.StatusMerged)
 	if err != nil {
 		slog.Error("Error updating PR", strconv.Itoa(prID), err)
 		return nil, err
 	}
 
-	return s.getMergedPRDetails(ctx, pr)
+	reason := "merged"
+	if !checkApprovals {
+		reason = "merge recorded from external system"
+	}
+	if err = s.recordStatusChange(ctx, pr, before, reason); err != nil {
+		return nil, err
+	}
+
+	merged, err := s.getMergedPRDetails(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.events.Publish(ctx, entity.EventPRMerged, merged); err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// MarkReady переводит черновик в OPEN и назначает ревьюверов.
+func (s *PRService) MarkReady(ctx context.Context, prID int) (*entity.PRDetailResponse, error) {
+	return s.openWithReviewers(ctx, prID, entity.StatusDraft)
+}
+
+// ReopenPR возвращает закрытый PR в OPEN и заново назначает ревьюверов.
+func (s *PRService) ReopenPR(ctx context.Context, prID int) (*entity.PRDetailResponse, error) {
+	return s.openWithReviewers(ctx, prID, entity.StatusClosed)
+}
+
+func (s *PRService) openWithReviewers(ctx context.Context, prID int, expectedStatus string) (*entity.PRDetailResponse, error) {
+	return inTx(ctx, s.uow, func(ctx context.Context) (*entity.PRDetailResponse, error) {
+		return s.openPR(ctx, prID, expectedStatus)
+	})
+}
+
+func (s *PRService) openPR(ctx context.Context, prID int, expectedStatus string) (*entity.PRDetailResponse, error) {
+	pr, err := s.prRepo.GetByIDForUpdate(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pr.Status != expectedStatus {
+		return nil, errors.New("invalid PR status transition")
+	}
+
+	if err = checkTransition(pr.Status, entity.StatusOpen); err != nil {
+		return nil, err
+	}
+
+	// Кандидаты - все команды автора, команда PR первой
+	teamIDs, err := s.userRepo.GetTeamsByUserID(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if pr.TeamID != nil {
+		teamIDs = slices.DeleteFunc(teamIDs, func(id int) bool { return id == *pr.TeamID })
+		teamIDs = append([]int{*pr.TeamID}, teamIDs...)
+	}
+
+	if len(teamIDs) == 0 {
+		return nil, errors.New("author is not in any team")
+	}
+
+	selected, explain, err := s.pickReviewers(ctx, pr.ID, teamIDs, pr.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := prState(pr)
+	pr, err = s.prRepo.UpdateStatus(ctx, pr.ID, entity.StatusOpen)
+	if err != nil {
+		slog.Error("Error updating PR", strconv.Itoa(prID), err)
+		return nil, err
+	}
+
+	if err = s.prRepo.SetDegraded(ctx, pr.ID, explain.Degraded); err != nil {
+		return nil, err
+	}
+	pr.Degraded = explain.Degraded
+
+	reason := "marked ready for review"
+	if expectedStatus == entity.StatusClosed {
+		reason = "reopened"
+	}
+	if err = s.recordStatusChange(ctx, pr, before, reason); err != nil {
+		return nil, err
+	}
+
+	if _, err = s.assignReviewers(ctx, pr.ID, selected, explain); err != nil {
+		return nil, err
+	}
+
+	details, err := s.getPRDetails(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	eventType := entity.EventPRReady
+	if expectedStatus == entity.StatusClosed {
+		eventType = entity.EventPRReopened
+	}
+	if err = s.events.Publish(ctx, eventType, details); err != nil {
+		return nil, err
+	}
+
+	details.Explain = explain
+	return details, nil
+}
+
+// ClosePR закрывает PR без merge и освобождает ревьюверов.
+func (s *PRService) ClosePR(ctx context.Context, prID int) (*entity.PRDetailResponse, error) {
+	return inTx(ctx, s.uow, func(ctx context.Context) (*entity.PRDetailResponse, error) {
+		return s.closePR(ctx, prID)
+	})
+}
+
+func (s *PRService) closePR(ctx context.Context, prID int) (*entity.PRDetailResponse, error) {
+	pr, err := s.prRepo.GetByIDForUpdate(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkTransition(pr.Status, entity.StatusClosed); err != nil {
+		return nil, err
+	}
+
+	// Снятые назначения остаются только в журнале аудита
+	reviewers, err := s.prRepo.GetReviewers(ctx, pr.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.prRepo.RemoveAllReviewers(ctx, pr.ID); err != nil {
+		slog.Error("Error releasing reviewers", strconv.Itoa(prID), err)
+		return nil, err
+	}
+
+	for _, reviewer := range reviewers {
+		if err = s.recordUnassigned(ctx, pr.ID, reviewer, "PR closed"); err != nil {
+			return nil, err
+		}
+	}
+
+	before := prState(pr)
+	pr, err = s.prRepo.UpdateStatus(ctx, pr.ID, entity.StatusClosed)
+	if err != nil {
+		slog.Error("Error updating PR", strconv.Itoa(prID), err)
+		return nil, err
+	}
+
+	if err = s.recordStatusChange(ctx, pr, before, "closed without merge"); err != nil {
+		return nil, err
+	}
+
+	closed, err := s.getPRDetails(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.events.Publish(ctx, entity.EventPRClosed, closed); err != nil {
+		return nil, err
+	}
+
+	return closed, nil
+}
+
+func (s *PRService) SubmitReview(ctx context.Context, req *entity.SubmitReviewRequest) (*entity.PRDetailResponse, error) {
+	return inTx(ctx, s.uow, func(ctx context.Context) (*entity.PRDetailResponse, error) {
+		return s.submitReview(ctx, req)
+	})
+}
+
+func (s *PRService) submitReview(ctx context.Context, req *entity.SubmitReviewRequest) (*entity.PRDetailResponse, error) {
+	switch req.Verdict {
+	case entity.VerdictApproved, entity.VerdictChangesRequested, entity.VerdictCommented:
+	default:
+		return nil, errors.New("unknown verdict")
+	}
+
+	// Блокировка PR не дает вердикту разойтись с параллельным merge
+	pr, err := s.prRepo.GetByIDForUpdate(ctx, req.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pr.Status == entity.StatusMerged {
+		return nil, errors.New("cannot review merged PR")
+	}
+	if pr.Status != entity.StatusOpen {
+		return nil, errors.New("PR is not open")
+	}
+
+	if err = s.prRepo.SetVerdict(ctx, pr.ID, req.ReviewerID, req.Verdict); err != nil {
+		slog.Error("Error setting verdict", strconv.Itoa(pr.ID), err.Error())
+		return nil, err
+	}
+
+	return s.getPRDetails(ctx, pr)
 }
 
 func (s *PRService) ReassignReviewer(ctx context.Context, prID int, oldReviewerID int) (*entity.PRDetailResponse, string, error) {
+	// Снятие старого и назначение нового ревьювера - одна транзакция
+	var prDetails *entity.PRDetailResponse
+	var replacedBy string
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		prDetails, replacedBy, err = s.reassignReviewer(ctx, prID, oldReviewerID)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return prDetails, replacedBy, nil
+}
+
+func (s *PRService) reassignReviewer(ctx context.Context, prID int, oldReviewerID int) (*entity.PRDetailResponse, string, error) {
 	pr, err := s.validatePRForReassignment(ctx, prID, oldReviewerID)
 	if err != nil {
 		slog.Error("Error validating PR", strconv.Itoa(prID), err)
@@ -144,82 +687,169 @@ func (s *PRService) ReassignReviewer(ctx context.Context, prID int, oldReviewerI
 		return nil, "", errors.New("user not found")
 	}
 
-	// Получаем команду старого ревьювера
-	teamIDs, err := s.userRepo.GetTeamsByUserID(ctx, oldReviewerID)
-	if err != nil || len(teamIDs) == 0 {
-		slog.Error("Error getting teamIDs or there is no team", err.Error(), nil)
-		return nil, "", errors.New("no active replacement candidate in team")
+	newReviewer, explain, err := s.replaceReviewer(ctx, pr, oldReviewerID, "reassigned")
+	if err != nil {
+		return nil, "", err
 	}
 
+	// Формируем ответ
+	prDetails, err := s.getPRDetails(ctx, pr)
+	if err != nil {
+		slog.Error("Error getting prDetails", strconv.Itoa(pr.ID), err.Error())
+		return nil, "", err
+	}
+	prDetails.Explain = explain
+
+	return prDetails, fmt.Sprintf("%d", newReviewer.UserID), nil
+}
+
+// replaceReviewer снимает ревьювера с PR и назначает замену по правилам команды,
+// из которой он был выбран. cause - причина замены для журнала аудита.
+func (s *PRService) replaceReviewer(ctx context.Context, pr *entity.PullRequest, oldReviewerID int, cause string) (*entity.ReviewerResponse, *entity.AssignmentExplanation, error) {
 	// Получаем текущих ревьюверов
 	currentReviewers, err := s.prRepo.GetReviewers(ctx, pr.ID)
 	if err != nil {
 		slog.Error("Error getting current Reviewers ", strconv.Itoa(pr.ID), err.Error())
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	// Создаем список ID текущих ревьюверов для исключения
-	excludeIDs := make(map[int]bool)
-	excludeIDs[pr.AuthorID] = true // Исключаем автора
+	excludeIDs := make(map[int]string)
+	excludeIDs[pr.AuthorID] = entity.ExcludedAuthor // Исключаем автора
+	var oldReviewer entity.ReviewerResponse
 	for _, r := range currentReviewers {
-		excludeIDs[r.UserID] = true
+		excludeIDs[r.UserID] = entity.ExcludedAlreadyAssigned
+		if r.UserID == oldReviewerID {
+			oldReviewer = r
+		}
 	}
 
-	// Ищем замену из первой команды
-	teamID := teamIDs[0]
-	candidates, err := s.teamRepo.GetActiveMembers(ctx, teamID, &pr.AuthorID)
+	// Замену ищем в команде, из которой был назначен старый ревьювер.
+	// Для ревьювера из резервной команды - в команде PR, для старых назначений - в командах ревьювера
+	var teamIDs []int
+	switch {
+	case oldReviewer.CrossTeam && pr.TeamID != nil:
+		teamIDs = []int{*pr.TeamID}
+	case oldReviewer.TeamID != 0:
+		teamIDs = []int{oldReviewer.TeamID}
+	default:
+		teamIDs, err = s.userRepo.GetTeamsByUserID(ctx, oldReviewerID)
+		if err != nil {
+			slog.Error("Error getting teamIDs", strconv.Itoa(oldReviewerID), err.Error())
+			return nil, nil, err
+		}
+	}
+
+	if len(teamIDs) == 0 {
+		slog.Warn("Reviewer has no team", "reviewer_id", oldReviewerID)
+		return nil, nil, errors.New("no active replacement candidate in team")
+	}
+
+	// Ищем замену, при необходимости - в резервных командах
+	settings, err := s.teamRepo.GetSettings(ctx, teamIDs[0])
 	if err != nil {
-		slog.Error("Error getting new reviewers", err.Error(), nil)
-		return nil, "", err
+		return nil, nil, err
 	}
 
-	// Фильтруем кандидатов
-	var availableCandidates []entity.UserResponse
-	for _, candidate := range candidates {
-		if !excludeIDs[candidate.UserID] {
-			availableCandidates = append(availableCandidates, candidate)
-		}
+	explain := s.newExplanation(settings, pr.ID)
+	selected, err := s.selectReviewers(ctx, teamIDs, settings, excludeIDs, 1, explain)
+	if err != nil {
+		slog.Error("Error selecting new reviewer", strconv.Itoa(pr.ID), err.Error())
+		return nil, nil, err
 	}
 
-	if len(availableCandidates) == 0 {
+	if len(selected) == 0 {
 		slog.Warn("No candidates")
-		return nil, "", errors.New("no active replacement candidate in team")
+		return nil, nil, &SelectionError{Message: "no active replacement candidate in team", Explain: explain}
 	}
 
-	// Выбираем случайного кандидата
-	newReviewer := availableCandidates[rand.Intn(len(availableCandidates))]
+	newReviewer := selected[0]
+	newReviewer.Reason = fmt.Sprintf("replaces user %d (%s), %s", oldReviewerID, cause, newReviewer.Reason)
 
 	// Удаляем старого ревьювера
 	if err = s.prRepo.RemoveReviewer(ctx, pr.ID, oldReviewerID); err != nil {
 		slog.Error("Error removing old reviewer", strconv.Itoa(pr.ID), err.Error())
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	// Добавляем нового ревьювера
-	if err = s.prRepo.AddReviewer(ctx, pr.ID, newReviewer.UserID); err != nil {
+	if err = s.prRepo.AddReviewer(ctx, pr.ID, newReviewer.UserID, newReviewer.TeamID, newReviewer.CrossTeam); err != nil {
 		slog.Error("Error adding newReviewer", strconv.Itoa(pr.ID), err.Error())
-		return nil, "", err
+		return nil, nil, err
 	}
 
-	// Формируем ответ
-	prDetails, err := s.getPRDetails(ctx, pr)
+	if err = s.recordUnassigned(ctx, pr.ID, oldReviewer, fmt.Sprintf("%s, replaced by user %d", cause, newReviewer.UserID)); err != nil {
+		return nil, nil, err
+	}
+	if err = s.recordAssigned(ctx, pr.ID, newReviewer, explain.Seed); err != nil {
+		return nil, nil, err
+	}
+
+	event := entity.ReviewerReplacedEvent{PullRequestID: pr.ID, OldReviewerID: oldReviewerID, NewReviewer: newReviewer}
+	if err = s.events.Publish(ctx, entity.EventReviewerReplaced, event); err != nil {
+		return nil, nil, err
+	}
+
+	return &newReviewer, explain, nil
+}
+
+// releaseReviewer снимает ревьювера с PR без замены и помечает PR деградированным,
+// если ревьюверов стало меньше минимума команды. cause - причина для журнала аудита.
+func (s *PRService) releaseReviewer(ctx context.Context, pr *entity.PullRequest, reviewerID int, cause string) error {
+	assigned, err := s.prRepo.GetReviewers(ctx, pr.ID)
 	if err != nil {
-		slog.Error("Error getting prDetails", strconv.Itoa(pr.ID), err.Error())
-		return nil, "", err
+		return err
 	}
 
-	return prDetails, fmt.Sprintf("%d", newReviewer.UserID), nil
+	if err := s.prRepo.RemoveReviewer(ctx, pr.ID, reviewerID); err != nil {
+		return err
+	}
+
+	for _, reviewer := range assigned {
+		if reviewer.UserID != reviewerID {
+			continue
+		}
+		if err := s.recordUnassigned(ctx, pr.ID, reviewer, cause+", no replacement candidate"); err != nil {
+			return err
+		}
+	}
+
+	if pr.TeamID == nil {
+		return nil
+	}
+
+	settings, err := s.teamRepo.GetSettings(ctx, *pr.TeamID)
+	if err != nil {
+		return err
+	}
+
+	reviewers, err := s.prRepo.GetReviewers(ctx, pr.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(reviewers) < settings.MinReviewers && !pr.Degraded {
+		if err := s.prRepo.SetDegraded(ctx, pr.ID, true); err != nil {
+			return err
+		}
+		pr.Degraded = true
+	}
+
+	return nil
 }
 
 func (s *PRService) validatePRForReassignment(ctx context.Context, prID, reviewerID int) (*entity.PullRequest, error) {
-	pr, err := s.prRepo.GetByID(ctx, prID)
+	pr, err := s.prRepo.GetByIDForUpdate(ctx, prID)
 	if err != nil {
 		return nil, err
 	}
 
-	if pr.Status == "MERGED" {
+	if pr.Status == entity.StatusMerged {
 		return nil, errors.New("cannot reassign on merged PR")
 	}
+	if pr.Status != entity.StatusOpen {
+		return nil, errors.New("PR is not open")
+	}
 
 	isAssigned, err := s.prRepo.IsReviewerAssigned(ctx, prID, reviewerID)
 	if err != nil {
@@ -239,22 +869,12 @@ func (s *PRService) getPRDetails(ctx context.Context, pr *entity.PullRequest) (*
 		return nil, err
 	}
 
-	reviewers, err := s.prRepo.GetReviewers(ctx, pr.ID)
+	reviewerResponses, err := s.prRepo.GetReviewers(ctx, pr.ID)
 	if err != nil {
-		reviewers = []entity.UserResponse{}
+		reviewerResponses = []entity.ReviewerResponse{}
 	}
 
-	var reviewerResponses []entity.UserResponse
-	for _, reviewer := range reviewers {
-		reviewerResponses = append(reviewerResponses, entity.UserResponse{
-			UserID:   reviewer.UserID,
-			Username: reviewer.Username,
-			IsActive: reviewer.IsActive,
-			TeamName: reviewer.TeamName,
-		})
-	}
-
-	return &entity.PRDetailResponse{
+	details := &entity.PRDetailResponse{
 		PullRequestID:   pr.ID,
 		PullRequestName: pr.Title,
 		Author: entity.UserResponse{
@@ -265,7 +885,13 @@ func (s *PRService) getPRDetails(ctx context.Context, pr *entity.PullRequest) (*
 		},
 		Status:    string(pr.Status),
 		Reviewers: reviewerResponses,
-	}, nil
+		Degraded:  pr.Degraded,
+	}
+	if pr.MergedAt.Valid {
+		details.MergedAt = &pr.MergedAt.Time
+	}
+
+	return details, nil
 }
 
 func (s *PRService) getMergedPRDetails(ctx context.Context, pr *entity.PullRequest) (*entity.MergedPRResponse, error) {
@@ -274,19 +900,9 @@ func (s *PRService) getMergedPRDetails(ctx context.Context, pr *entity.PullReque
 		return nil, err
 	}
 
-	reviewers, err := s.prRepo.GetReviewers(ctx, pr.ID)
+	reviewerResponses, err := s.prRepo.GetReviewers(ctx, pr.ID)
 	if err != nil {
-		reviewers = []entity.UserResponse{}
-	}
-
-	var reviewerResponses []entity.UserResponse
-	for _, reviewer := range reviewers {
-		reviewerResponses = append(reviewerResponses, entity.UserResponse{
-			UserID:   reviewer.UserID,
-			Username: reviewer.Username,
-			IsActive: reviewer.IsActive,
-			TeamName: reviewer.TeamName,
-		})
+		reviewerResponses = []entity.ReviewerResponse{}
 	}
 
 	return &entity.MergedPRResponse{
@@ -303,3 +919,75 @@ func (s *PRService) getMergedPRDetails(ctx context.Context, pr *entity.PullReque
 		MergedAt:  pr.MergedAt,
 	}, nil
 }
+
+// recordAssigned пишет назначение ревьювера вместе с зерном выбора, по которому его можно воспроизвести.
+func (s *PRService) recordAssigned(ctx context.Context, prID int, reviewer entity.ReviewerResponse, seed int64) error {
+	return s.audit.record(ctx, entity.AuditEntry{
+		Action:        entity.AuditReviewerAssigned,
+		PullRequestID: prID,
+		UserID:        reviewer.UserID,
+		TeamID:        reviewer.TeamID,
+		Reason:        reviewer.Reason,
+	}, nil, assignmentState{ReviewerResponse: reviewer, Seed: seed})
+}
+
+func (s *PRService) recordUnassigned(ctx context.Context, prID int, reviewer entity.ReviewerResponse, reason string) error {
+	return s.audit.record(ctx, entity.AuditEntry{
+		Action:        entity.AuditReviewerUnassigned,
+		PullRequestID: prID,
+		UserID:        reviewer.UserID,
+		TeamID:        reviewer.TeamID,
+		Reason:        reason,
+	}, reviewer, nil)
+}
+
+func (s *PRService) recordStatusChange(ctx context.Context, pr *entity.PullRequest, before map[string]any, reason string) error {
+	entry := entity.AuditEntry{
+		Action:        entity.AuditPRStatusChanged,
+		PullRequestID: pr.ID,
+		Reason:        reason,
+	}
+	if pr.TeamID != nil {
+		entry.TeamID = *pr.TeamID
+	}
+
+	return s.audit.record(ctx, entry, before, prState(pr))
+}
+
+// SelectionError - отказ в выборе ревьюверов. Explain объясняет, почему кандидатов не хватило.
+type SelectionError struct {
+	Message string
+	Explain *entity.AssignmentExplanation
+}
+
+func (e *SelectionError) Error() string {
+	return e.Message
+}
+
+// newExplanation начинает объяснение выбора по настройкам команды, из которой выбираются ревьюверы,
+// и берет зерно для выбора ревьюверов PR prID.
+func (s *PRService) newExplanation(settings *entity.TeamSettings, prID int) *entity.AssignmentExplanation {
+	return &entity.AssignmentExplanation{
+		Seed:           s.seeds.next(prID),
+		Strategy:       settings.ReviewerStrategy,
+		ReviewersPerPR: settings.ReviewersPerPR,
+		MinReviewers:   settings.MinReviewers,
+		Pools:          []entity.CandidatePool{},
+	}
+}
+
+// assignmentState - состояние назначения в журнале аудита: ревьювер и зерно выбора
+type assignmentState struct {
+	entity.ReviewerResponse
+	Seed int64 `json:"seed"`
+}
+
+// prState - состояние PR, которое пишется в журнал аудита
+func prState(pr *entity.PullRequest) map[string]any {
+	return map[string]any{
+		"title":     pr.Title,
+		"author_id": pr.AuthorID,
+		"status":    pr.Status,
+		"degraded":  pr.Degraded,
+	}
+}
@@ -0,0 +1,546 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+	"PR-appointer/internal/service"
+	"PR-appointer/internal/storage/storagetest"
+)
+
+// fixture - сервисы над одним хранилищем и команда "backend", созданная в нем.
+type fixture struct {
+	t       *testing.T
+	ctx     context.Context
+	prs     *service.PRService
+	users   *service.UserService
+	teams   *service.TeamService
+	members []entity.UserResponse
+}
+
+// newFixture создает команду "backend" из size активных участников.
+func newFixture(t *testing.T, store *repository.Store, size int) *fixture {
+	t.Helper()
+
+	seeds := service.NewSeedSource(service.SeedConfig{Seed: 1})
+	f := &fixture{
+		t:     t,
+		ctx:   context.Background(),
+		prs:   service.NewPRService(store, seeds),
+		users: service.NewUserService(store, seeds),
+		teams: service.NewTeamService(store),
+	}
+	f.members = f.addTeam("backend", size).Members
+
+	return f
+}
+
+func (f *fixture) addTeam(name string, size int) *entity.TeamResponse {
+	f.t.Helper()
+
+	req := &entity.TeamCreateRequest{TeamName: name}
+	for i := 1; i <= size; i++ {
+		req.Members = append(req.Members, entity.TeamMemberRequest{
+			Username: fmt.Sprintf("%s-%d", name, i),
+			IsActive: true,
+		})
+	}
+
+	team, err := f.teams.CreateTeam(f.ctx, req)
+	if err != nil {
+		f.t.Fatalf("create team %s: %v", name, err)
+	}
+	return team
+}
+
+// author - первый участник команды, автор PR в тестах.
+func (f *fixture) author() int {
+	return f.members[0].UserID
+}
+
+func (f *fixture) createPR(prID int, draft bool) *entity.PRDetailResponse {
+	f.t.Helper()
+
+	pr, err := f.prs.CreatePR(f.ctx, &entity.PRCreateRequest{
+		PullRequestID:   prID,
+		PullRequestName: fmt.Sprintf("PR %d", prID),
+		AuthorID:        f.author(),
+		Draft:           draft,
+	})
+	if err != nil {
+		f.t.Fatalf("create PR %d: %v", prID, err)
+	}
+	return pr
+}
+
+func (f *fixture) setSettings(req *entity.TeamSettingsRequest) {
+	f.t.Helper()
+
+	req.TeamName = "backend"
+	if _, err := f.teams.UpdateSettings(f.ctx, req); err != nil {
+		f.t.Fatalf("update settings: %v", err)
+	}
+}
+
+func expectError(t *testing.T, err error, want string) {
+	t.Helper()
+
+	if err == nil {
+		t.Errorf("got no error, want %q", want)
+		return
+	}
+	if err.Error() != want {
+		t.Errorf("got error %q, want %q", err.Error(), want)
+	}
+}
+
+func reviewerIDs(reviewers []entity.ReviewerResponse) map[int]bool {
+	ids := make(map[int]bool, len(reviewers))
+	for _, r := range reviewers {
+		ids[r.UserID] = true
+	}
+	return ids
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestCreatePR(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		f := newFixture(t, store, 5)
+
+		pr := f.createPR(1, false)
+		if pr.Status != entity.StatusOpen {
+			t.Errorf("status %s, want OPEN", pr.Status)
+		}
+		if len(pr.Reviewers) != 2 {
+			t.Fatalf("%d reviewers, want 2", len(pr.Reviewers))
+		}
+		if reviewerIDs(pr.Reviewers)[f.author()] {
+			t.Error("author is assigned as reviewer")
+		}
+
+		draft := f.createPR(2, true)
+		if draft.Status != entity.StatusDraft || len(draft.Reviewers) != 0 {
+			t.Errorf("draft: status %s with %d reviewers, want DRAFT without reviewers", draft.Status, len(draft.Reviewers))
+		}
+
+		tests := []struct {
+			name string
+			req  entity.PRCreateRequest
+			want string
+		}{
+			{"duplicate id", entity.PRCreateRequest{PullRequestID: 1, PullRequestName: "dup", AuthorID: f.author()}, "PR already exists"},
+			{"unknown author", entity.PRCreateRequest{PullRequestID: 3, PullRequestName: "x", AuthorID: 9999}, "author not found"},
+			{"unknown team", entity.PRCreateRequest{PullRequestID: 3, PullRequestName: "x", AuthorID: f.author(), TeamName: "nope"}, "team not found"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := f.prs.CreatePR(f.ctx, &tt.req)
+				expectError(t, err, tt.want)
+			})
+		}
+
+		// Отказ при нехватке кандидатов откатывает создание целиком
+		f.setSettings(&entity.TeamSettingsRequest{
+			ReviewersPerPR:     ptr(5),
+			MinReviewers:       ptr(5),
+			FailOnInsufficient: ptr(true),
+		})
+		_, err := f.prs.CreatePR(f.ctx, &entity.PRCreateRequest{PullRequestID: 3, PullRequestName: "x", AuthorID: f.author()})
+		expectError(t, err, "not enough active reviewers in team")
+		if _, err := f.prs.GetPR(f.ctx, 3); err == nil {
+			t.Error("PR 3 exists after failed create")
+		}
+	})
+}
+
+func TestReviewerCapacity(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		f := newFixture(t, store, 3)
+
+		_, err := f.teams.UpdateSettings(f.ctx, &entity.TeamSettingsRequest{TeamName: "backend", MaxOpenReviews: ptr(-1)})
+		expectError(t, err, "max_open_reviews must be at least 0")
+
+		f.setSettings(&entity.TeamSettingsRequest{ReviewersPerPR: ptr(1), MaxOpenReviews: ptr(1)})
+		busy := f.createPR(1, false).Reviewers[0].UserID
+
+		pr := f.createPR(2, false)
+		if len(pr.Reviewers) != 1 || pr.Reviewers[0].UserID == busy {
+			t.Fatalf("reviewers %v, want one reviewer other than %d", pr.Reviewers, busy)
+		}
+
+		pool := pr.Explain.Pools[0]
+		if slices.Contains(pool.Candidates, busy) {
+			t.Errorf("reviewer %d at capacity is a candidate", busy)
+		}
+		i := slices.IndexFunc(pool.Excluded, func(c entity.ExcludedCandidate) bool { return c.UserID == busy })
+		if i < 0 || pool.Excluded[i].Reason != entity.ExcludedOverCapacity {
+			t.Errorf("excluded %+v, want %d with reason %s", pool.Excluded, busy, entity.ExcludedOverCapacity)
+		}
+
+		// Оба возможных ревьювера на пределе: PR создается без ревьюверов
+		if pr := f.createPR(3, false); len(pr.Reviewers) != 0 {
+			t.Errorf("reviewers %v, want none", pr.Reviewers)
+		}
+	})
+}
+
+func TestAssignmentSeed(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		f := newFixture(t, store, 4)
+		// Зерно выводится из id PR: 100 + id
+		f.prs = service.NewPRService(store, service.NewSeedSource(service.SeedConfig{Seed: 100, PerPR: true}))
+
+		pr := f.createPR(1, false)
+		if pr.Explain.Seed != 101 {
+			t.Errorf("explain seed %d, want 101", pr.Explain.Seed)
+		}
+
+		entries, err := service.NewAuditService(store).List(f.ctx, &entity.AuditRequest{PullRequestID: 1})
+		if err != nil {
+			t.Fatalf("list audit: %v", err)
+		}
+		assigned := 0
+		for _, entry := range entries {
+			if entry.Action != entity.AuditReviewerAssigned {
+				continue
+			}
+			assigned++
+
+			var after struct {
+				UserID int   `json:"user_id"`
+				Seed   int64 `json:"seed"`
+			}
+			if err := json.Unmarshal(entry.After, &after); err != nil {
+				t.Fatalf("decode audit state %s: %v", entry.After, err)
+			}
+			if after.UserID != entry.UserID || after.Seed != 101 {
+				t.Errorf("audit state %s, want user %d with seed 101", entry.After, entry.UserID)
+			}
+		}
+		if assigned != len(pr.Reviewers) {
+			t.Errorf("%d assignments in audit log, want %d", assigned, len(pr.Reviewers))
+		}
+
+		// Отказ при нехватке кандидатов тоже объясняется вместе с зерном
+		f.setSettings(&entity.TeamSettingsRequest{
+			ReviewersPerPR:     ptr(4),
+			MinReviewers:       ptr(4),
+			FailOnInsufficient: ptr(true),
+		})
+		_, err = f.prs.CreatePR(f.ctx, &entity.PRCreateRequest{PullRequestID: 2, PullRequestName: "x", AuthorID: f.author()})
+		expectError(t, err, "not enough active reviewers in team")
+
+		var selectionErr *service.SelectionError
+		if !errors.As(err, &selectionErr) {
+			t.Fatalf("error %T, want *service.SelectionError", err)
+		}
+		if selectionErr.Explain.Seed != 102 || len(selectionErr.Explain.Pools) == 0 {
+			t.Errorf("failure explanation %+v, want seed 102 with candidate pools", selectionErr.Explain)
+		}
+	})
+}
+
+func TestMergePR(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		f := newFixture(t, store, 5)
+		pr := f.createPR(1, false)
+
+		merged, err := f.prs.MergePR(f.ctx, 1)
+		if err != nil {
+			t.Fatalf("merge: %v", err)
+		}
+		if merged.Status != entity.StatusMerged || !merged.MergedAt.Valid {
+			t.Errorf("merged: status %s, merged_at set %v", merged.Status, merged.MergedAt.Valid)
+		}
+
+		again, err := f.prs.MergePR(f.ctx, 1)
+		if err != nil {
+			t.Fatalf("repeated merge: %v", err)
+		}
+		if !again.MergedAt.Time.Equal(merged.MergedAt.Time) {
+			t.Errorf("repeated merge changed merged_at: %v -> %v", merged.MergedAt.Time, again.MergedAt.Time)
+		}
+
+		_, _, err = f.prs.ReassignReviewer(f.ctx, 1, pr.Reviewers[0].UserID)
+		expectError(t, err, "cannot reassign on merged PR")
+
+		_, err = f.prs.ClosePR(f.ctx, 1)
+		expectError(t, err, "invalid PR status transition")
+
+		_, err = f.prs.MergePR(f.ctx, 404)
+		expectError(t, err, "PR not found")
+
+		// Merge ждет нужного числа одобрений
+		f.setSettings(&entity.TeamSettingsRequest{RequiredApprovals: ptr(1)})
+		second := f.createPR(2, false)
+		_, err = f.prs.MergePR(f.ctx, 2)
+		expectError(t, err, "not enough approvals to merge")
+
+		_, err = f.prs.SubmitReview(f.ctx, &entity.SubmitReviewRequest{
+			PullRequestID: 2,
+			ReviewerID:    second.Reviewers[0].UserID,
+			Verdict:       entity.VerdictApproved,
+		})
+		if err != nil {
+			t.Fatalf("approve: %v", err)
+		}
+		if _, err = f.prs.MergePR(f.ctx, 2); err != nil {
+			t.Errorf("merge after approval: %v", err)
+		}
+	})
+}
+
+func TestReassignReviewer(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		f := newFixture(t, store, 5)
+		pr := f.createPR(1, false)
+		old := pr.Reviewers[0].UserID
+
+		updated, replacedBy, err := f.prs.ReassignReviewer(f.ctx, 1, old)
+		if err != nil {
+			t.Fatalf("reassign: %v", err)
+		}
+		ids := reviewerIDs(updated.Reviewers)
+		if ids[old] || ids[f.author()] || len(ids) != 2 {
+			t.Errorf("reviewers after reassign: %v (old %d, author %d)", ids, old, f.author())
+		}
+		if replacedBy == fmt.Sprint(old) {
+			t.Errorf("replaced by the same reviewer %s", replacedBy)
+		}
+
+		_, _, err = f.prs.ReassignReviewer(f.ctx, 1, old)
+		expectError(t, err, "reviewer is not assigned to this PR")
+
+		_, _, err = f.prs.ReassignReviewer(f.ctx, 404, old)
+		expectError(t, err, "PR not found")
+	})
+}
+
+func TestReassignWithoutCandidate(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		// Автор и два ревьювера - заменить некем
+		f := newFixture(t, store, 3)
+		pr := f.createPR(1, false)
+
+		_, _, err := f.prs.ReassignReviewer(f.ctx, 1, pr.Reviewers[0].UserID)
+		expectError(t, err, "no active replacement candidate in team")
+
+		// Ошибка откатывает снятие старого ревьювера
+		details, err := f.prs.GetPRDetails(f.ctx, 1)
+		if err != nil {
+			t.Fatalf("get PR: %v", err)
+		}
+		if len(details.Reviewers) != 2 {
+			t.Errorf("%d reviewers after failed reassign, want 2", len(details.Reviewers))
+		}
+	})
+}
+
+func TestConcurrentReassignReviewer(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		// На SQLite GetByIDForUpdate не блокирует строку: очередность дает BEGIN IMMEDIATE
+		f := newFixture(t, store, 6)
+		pr := f.createPR(1, false)
+		old := pr.Reviewers[0].UserID
+
+		errs := make([]error, 10)
+		var wg sync.WaitGroup
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _, errs[i] = f.prs.ReassignReviewer(f.ctx, 1, old)
+			}()
+		}
+		wg.Wait()
+
+		succeeded := 0
+		for _, err := range errs {
+			switch {
+			case err == nil:
+				succeeded++
+			case err.Error() != "reviewer is not assigned to this PR":
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+		if succeeded != 1 {
+			t.Errorf("%d reassigns succeeded, want 1", succeeded)
+		}
+	})
+}
+
+func TestCloseAndReopenPR(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		f := newFixture(t, store, 5)
+		pr := f.createPR(1, false)
+
+		closed, err := f.prs.ClosePR(f.ctx, 1)
+		if err != nil {
+			t.Fatalf("close: %v", err)
+		}
+		if closed.Status != entity.StatusClosed || len(closed.Reviewers) != 0 {
+			t.Errorf("closed: status %s with %d reviewers, want CLOSED without reviewers", closed.Status, len(closed.Reviewers))
+		}
+
+		_, err = f.prs.ClosePR(f.ctx, 1)
+		expectError(t, err, "invalid PR status transition")
+
+		_, _, err = f.prs.ReassignReviewer(f.ctx, 1, pr.Reviewers[0].UserID)
+		expectError(t, err, "PR is not open")
+
+		_, err = f.prs.MarkReady(f.ctx, 1)
+		expectError(t, err, "invalid PR status transition")
+
+		reopened, err := f.prs.ReopenPR(f.ctx, 1)
+		if err != nil {
+			t.Fatalf("reopen: %v", err)
+		}
+		if reopened.Status != entity.StatusOpen || len(reopened.Reviewers) != 2 {
+			t.Errorf("reopened: status %s with %d reviewers, want OPEN with 2", reopened.Status, len(reopened.Reviewers))
+		}
+
+		_, err = f.prs.ReopenPR(f.ctx, 1)
+		expectError(t, err, "invalid PR status transition")
+
+		_, err = f.prs.ClosePR(f.ctx, 404)
+		expectError(t, err, "PR not found")
+	})
+}
+
+func TestMarkReady(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		f := newFixture(t, store, 5)
+		f.createPR(1, true)
+
+		_, _, err := f.prs.ReassignReviewer(f.ctx, 1, f.members[1].UserID)
+		expectError(t, err, "PR is not open")
+
+		ready, err := f.prs.MarkReady(f.ctx, 1)
+		if err != nil {
+			t.Fatalf("mark ready: %v", err)
+		}
+		if ready.Status != entity.StatusOpen || len(ready.Reviewers) != 2 {
+			t.Errorf("ready: status %s with %d reviewers, want OPEN with 2", ready.Status, len(ready.Reviewers))
+		}
+
+		_, err = f.prs.MarkReady(f.ctx, 1)
+		expectError(t, err, "invalid PR status transition")
+	})
+}
+
+func TestStatusChangeEvents(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		f := newFixture(t, store, 5)
+		f.createPR(1, true)
+
+		if _, err := f.prs.MarkReady(f.ctx, 1); err != nil {
+			t.Fatalf("mark ready: %v", err)
+		}
+		if _, err := f.prs.ClosePR(f.ctx, 1); err != nil {
+			t.Fatalf("close: %v", err)
+		}
+		// Отклоненный переход откатывается вместе с событием
+		if _, err := f.prs.ClosePR(f.ctx, 1); err == nil {
+			t.Fatal("repeated close succeeded")
+		}
+		if _, err := f.prs.ReopenPR(f.ctx, 1); err != nil {
+			t.Fatalf("reopen: %v", err)
+		}
+
+		events, err := store.Outbox.ClaimDue(f.ctx, 100, time.Minute)
+		if err != nil {
+			t.Fatalf("claim outbox: %v", err)
+		}
+		var types []string
+		for _, event := range events {
+			if strings.HasPrefix(event.EventType, "pr.") {
+				types = append(types, event.EventType)
+			}
+		}
+
+		want := []string{entity.EventPRCreated, entity.EventPRReady, entity.EventPRClosed, entity.EventPRReopened}
+		if !slices.Equal(types, want) {
+			t.Errorf("PR events %v, want %v", types, want)
+		}
+	})
+}
+
+func TestBulkDeactivate(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		f := newFixture(t, store, 5)
+		pr := f.createPR(1, false)
+		old := pr.Reviewers[0].UserID
+
+		resp, err := f.users.BulkDeactivate(f.ctx, &entity.BulkDeactivateRequest{UserIDs: []int{old}})
+		if err != nil {
+			t.Fatalf("bulk deactivate: %v", err)
+		}
+		if len(resp.Deactivated) != 1 || resp.Deactivated[0].IsActive || resp.Deactivated[0].TeamName != "backend" {
+			t.Errorf("deactivated: %+v", resp.Deactivated)
+		}
+		if len(resp.Reassigned) != 1 || resp.Reassigned[0].OldReviewerID != old {
+			t.Fatalf("reassigned: %+v", resp.Reassigned)
+		}
+
+		details, err := f.prs.GetPRDetails(f.ctx, 1)
+		if err != nil {
+			t.Fatalf("get PR: %v", err)
+		}
+		if reviewerIDs(details.Reviewers)[old] {
+			t.Error("deactivated reviewer is still assigned")
+		}
+
+		tests := []struct {
+			name string
+			req  entity.BulkDeactivateRequest
+			want string
+		}{
+			{"empty request", entity.BulkDeactivateRequest{}, "user_ids or team_name is required"},
+			{"unknown user", entity.BulkDeactivateRequest{UserIDs: []int{9999}}, "user not found"},
+			{"unknown team", entity.BulkDeactivateRequest{TeamName: "nope"}, "team not found"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := f.users.BulkDeactivate(f.ctx, &tt.req)
+				expectError(t, err, tt.want)
+			})
+		}
+	})
+}
+
+func TestBulkDeactivateTeam(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		f := newFixture(t, store, 3)
+		f.createPR(1, false)
+
+		// Команда деактивирована целиком: замен нет, места ревьюверов освобождаются
+		resp, err := f.users.BulkDeactivate(f.ctx, &entity.BulkDeactivateRequest{TeamName: "backend"})
+		if err != nil {
+			t.Fatalf("bulk deactivate: %v", err)
+		}
+		if len(resp.Deactivated) != 3 || len(resp.Reassigned) != 0 || len(resp.Unfilled) != 2 {
+			t.Errorf("deactivated %d, reassigned %d, unfilled %d; want 3, 0, 2",
+				len(resp.Deactivated), len(resp.Reassigned), len(resp.Unfilled))
+		}
+
+		details, err := f.prs.GetPRDetails(f.ctx, 1)
+		if err != nil {
+			t.Fatalf("get PR: %v", err)
+		}
+		if len(details.Reviewers) != 0 {
+			t.Errorf("%d reviewers left, want 0", len(details.Reviewers))
+		}
+	})
+}
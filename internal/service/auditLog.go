@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+)
+
+type actorKey struct{}
+
+// WithActor запоминает в контексте, от чьего имени выполняются изменения, чтобы записать это в журнал аудита.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func actorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return entity.ActorAnonymous
+}
+
+// auditLog пишет журнал аудита в транзакции изменения, которое в нем описано.
+type auditLog struct {
+	auditRepo repository.AuditRepository
+}
+
+func newAuditLog(store *repository.Store) *auditLog {
+	return &auditLog{auditRepo: store.Audit}
+}
+
+// record дополняет запись исполнителем и состояниями до и после изменения; nil - состояния нет.
+func (a *auditLog) record(ctx context.Context, entry entity.AuditEntry, before, after any) error {
+	entry.Actor = actorFrom(ctx)
+
+	var err error
+	if entry.Before, err = encodeState(before); err != nil {
+		return err
+	}
+	if entry.After, err = encodeState(after); err != nil {
+		return err
+	}
+
+	return a.auditRepo.Add(ctx, &entry)
+}
+
+func encodeState(state any) (json.RawMessage, error) {
+	if state == nil {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit state: %w", err)
+	}
+	return encoded, nil
+}
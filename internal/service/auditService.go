@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 500
+)
+
+type AuditService struct {
+	auditRepo repository.AuditRepository
+	teamRepo  repository.TeamRepository
+}
+
+func NewAuditService(store *repository.Store) *AuditService {
+	return &AuditService{
+		auditRepo: store.Audit,
+		teamRepo:  store.Teams,
+	}
+}
+
+// List возвращает записи журнала, новые первыми. Интервал времени - [From, To).
+func (s *AuditService) List(ctx context.Context, req *entity.AuditRequest) ([]entity.AuditEntry, error) {
+	if req.From != nil && req.To != nil && !req.From.Before(*req.To) {
+		return nil, errors.New("from must be before to")
+	}
+
+	filter := entity.AuditFilter{
+		PullRequestID: req.PullRequestID,
+		UserID:        req.UserID,
+		From:          req.From,
+		To:            req.To,
+		Limit:         req.Limit,
+	}
+	if req.TeamName != "" {
+		team, err := s.teamRepo.GetByName(ctx, req.TeamName)
+		if err != nil {
+			return nil, err
+		}
+		filter.TeamID = team.ID
+	}
+
+	switch {
+	case filter.Limit <= 0:
+		filter.Limit = defaultAuditLimit
+	case filter.Limit > maxAuditLimit:
+		filter.Limit = maxAuditLimit
+	}
+
+	return s.auditRepo.List(ctx, filter)
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+)
+
+// tokenPrefix помогает отличать токены сервиса в логах и при поиске утечек
+const tokenPrefix = "pra_"
+
+type AuthService struct {
+	tokenRepo repository.TokenRepository
+	userRepo  repository.UserRepository
+	// jwt - проверка токенов SSO; nil, если JWT не настроены
+	jwt *JWTVerifier
+}
+
+func NewAuthService(store *repository.Store, jwt *JWTVerifier) *AuthService {
+	return &AuthService{
+		tokenRepo: store.Tokens,
+		userRepo:  store.Users,
+		jwt:       jwt,
+	}
+}
+
+// IssueToken выпускает токен с ролью role. Все роли, кроме бота, действуют от имени пользователя username.
+// Открытое значение возвращается только здесь, в базе хранится его хеш.
+func (s *AuthService) IssueToken(ctx context.Context, name, role, username string) (*entity.IssuedToken, error) {
+	if name == "" {
+		return nil, errors.New("token name is required")
+	}
+	if !isKnownRole(role) {
+		return nil, errors.New("unknown role")
+	}
+	if username == "" && role != entity.RoleBot {
+		return nil, errors.New("user is required for this role")
+	}
+
+	var userID *int
+	if username != "" {
+		user, err := s.userRepo.GetByUsername(ctx, username)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, errors.New("user not found")
+		}
+		userID = &user.ID
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := tokenPrefix + hex.EncodeToString(raw)
+
+	stored, err := s.tokenRepo.Create(ctx, name, hashToken(token), role, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.IssuedToken{APIToken: *stored, Token: token}, nil
+}
+
+func (s *AuthService) RevokeToken(ctx context.Context, tokenID int) error {
+	return s.tokenRepo.Revoke(ctx, tokenID)
+}
+
+// Authenticate находит вызывающего по открытому значению API-токена или по JWT.
+func (s *AuthService) Authenticate(ctx context.Context, token string) (*entity.Principal, error) {
+	if token == "" {
+		return nil, errors.New("invalid token")
+	}
+	if s.jwt != nil && !strings.HasPrefix(token, tokenPrefix) {
+		return s.authenticateJWT(ctx, token)
+	}
+
+	principal, err := s.tokenRepo.GetPrincipal(ctx, hashToken(token))
+	if err != nil {
+		if err.Error() == "token not found" {
+			return nil, errors.New("invalid token")
+		}
+		return nil, err
+	}
+
+	return principal, nil
+}
+
+// authenticateJWT сопоставляет пользователя SSO с users.username.
+// Пользователь должен уже существовать в сервисе и быть активным.
+func (s *AuthService) authenticateJWT(ctx context.Context, token string) (*entity.Principal, error) {
+	username, role, err := s.jwt.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		return nil, errors.New("invalid token")
+	}
+
+	return &entity.Principal{
+		Role:     role,
+		UserID:   user.ID,
+		Username: user.Username,
+	}, nil
+}
+
+func isKnownRole(role string) bool {
+	switch role {
+	case entity.RoleAdmin, entity.RoleTeamLead, entity.RoleMember, entity.RoleBot:
+		return true
+	}
+	return false
+}
+
+// hashToken - токены случайные и длинные, поэтому соль и медленный хеш не нужны
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+)
+
+// EventPublisher записывает доменные события.
+// Publish вызывается внутри транзакции операции, поэтому событие остается, только если она зафиксирована.
+type EventPublisher interface {
+	Publish(ctx context.Context, eventType string, data any) error
+}
+
+// outboxPublisher пишет событие в outbox; приемникам его передает OutboxDispatcher.
+type outboxPublisher struct {
+	outboxRepo repository.OutboxRepository
+}
+
+func newOutboxPublisher(store *repository.Store) *outboxPublisher {
+	return &outboxPublisher{outboxRepo: store.Outbox}
+}
+
+func (p *outboxPublisher) Publish(ctx context.Context, eventType string, data any) error {
+	eventID, err := newEventID()
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(entity.Event{
+		ID:        eventID,
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	return p.outboxRepo.Add(ctx, eventID, eventType, payload)
+}
+
+func newEventID() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate event id: %w", err)
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+func isKnownEvent(eventType string) bool {
+	switch eventType {
+	case entity.EventPRCreated, entity.EventReviewerAssigned, entity.EventReviewerReplaced,
+		entity.EventPRMerged, entity.EventPRClosed, entity.EventPRReady, entity.EventPRReopened,
+		entity.EventUserStatusChanged:
+		return true
+	}
+	return false
+}
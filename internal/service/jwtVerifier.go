@@ -0,0 +1,281 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"PR-appointer/internal/entity"
+)
+
+// JWTConfig - параметры проверки JWT, выданных внешним SSO.
+type JWTConfig struct {
+	// KeysFile - JWKS (JSON) или PEM с публичными ключами и/или сертификатами
+	KeysFile      string
+	UsernameClaim string
+	// RoleClaim - claim с ролью сервиса; пустой - все пользователи SSO получают роль member
+	RoleClaim string
+	Issuer    string
+	Audience  string
+}
+
+// JWTVerifier проверяет подпись и срок действия JWT ключами, загруженными при старте.
+// Симметричные алгоритмы (HS*) не принимаются: у сервиса есть только публичные ключи.
+type JWTVerifier struct {
+	cfg    JWTConfig
+	keys   *keySet
+	parser *jwt.Parser
+}
+
+// keySet - загруженные ключи. Ключ без kid подходит для любого токена,
+// поэтому такие ключи хранятся списком, а не под пустым kid, где они затирали бы друг друга.
+type keySet struct {
+	byKid map[string]crypto.PublicKey
+	noKid []crypto.PublicKey
+}
+
+func newKeySet() *keySet {
+	return &keySet{byKid: make(map[string]crypto.PublicKey)}
+}
+
+func (s *keySet) add(kid string, key crypto.PublicKey) {
+	if kid == "" {
+		s.noKid = append(s.noKid, key)
+		return
+	}
+	s.byKid[kid] = key
+}
+
+func (s *keySet) len() int {
+	return len(s.byKid) + len(s.noKid)
+}
+
+func NewJWTVerifier(cfg JWTConfig) (*JWTVerifier, error) {
+	if cfg.UsernameClaim == "" {
+		return nil, errors.New("jwt username claim is required")
+	}
+
+	data, err := os.ReadFile(cfg.KeysFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwt keys: %w", err)
+	}
+
+	var keys *keySet
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		keys, err = parseJWKS(data)
+	} else {
+		keys, err = parsePEMKeys(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if keys.len() == 0 {
+		return nil, errors.New("no jwt keys found in " + cfg.KeysFile)
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+
+	return &JWTVerifier{cfg: cfg, keys: keys, parser: jwt.NewParser(options...)}, nil
+}
+
+// Verify проверяет токен и возвращает логин и роль из claims.
+func (v *JWTVerifier) Verify(token string) (username, role string, err error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.keyFor); err != nil {
+		return "", "", errors.New("invalid token")
+	}
+
+	username, _ = claims[v.cfg.UsernameClaim].(string)
+	if username == "" {
+		return "", "", errors.New("invalid token")
+	}
+
+	role = entity.RoleMember
+	if v.cfg.RoleClaim != "" {
+		if claimed, _ := claims[v.cfg.RoleClaim].(string); isKnownRole(claimed) {
+			role = claimed
+		}
+	}
+
+	return username, role, nil
+}
+
+// keyFor выбирает ключ по kid. Подпись токена без kid проверяется всеми ключами по очереди,
+// токена с незнакомым kid - ключами без kid.
+func (v *JWTVerifier) keyFor(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := v.keys.byKid[kid]; ok && kid != "" {
+		return key, nil
+	}
+
+	set := jwt.VerificationKeySet{}
+	for _, key := range v.keys.noKid {
+		set.Keys = append(set.Keys, key)
+	}
+	if kid == "" {
+		for _, key := range v.keys.byKid {
+			set.Keys = append(set.Keys, key)
+		}
+	}
+	if len(set.Keys) == 0 {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	return set, nil
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS разбирает набор ключей RSA, EC (P-256/384/521) и OKP (Ed25519).
+// Ключи шифрования (use=enc) пропускаются.
+func parseJWKS(data []byte) (*keySet, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse jwks: %w", err)
+	}
+
+	keys := newKeySet()
+	for _, jwk := range set.Keys {
+		if jwk.Use == "enc" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwks key %q: %w", jwk.Kid, err)
+		}
+		keys.add(jwk.Kid, key)
+	}
+
+	return keys, nil
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(raw) == 0 {
+		return nil, errors.New("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
+
+// parsePEMKeys читает блоки PUBLIC KEY, RSA PUBLIC KEY и CERTIFICATE.
+// kid берется из заголовка блока, если он задан; ключ без kid проверяет токены без kid или с незнакомым kid.
+func parsePEMKeys(data []byte) (*keySet, error) {
+	keys := newKeySet()
+	for i := 0; ; i++ {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		var key crypto.PublicKey
+		var err error
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case "CERTIFICATE":
+			var cert *x509.Certificate
+			cert, err = x509.ParseCertificate(block.Bytes)
+			if err == nil {
+				key = cert.PublicKey
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse pem block %d: %w", i, err)
+		}
+
+		keys.add(block.Headers["kid"], key)
+	}
+
+	return keys, nil
+}
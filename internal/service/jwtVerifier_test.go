@@ -0,0 +1,107 @@
+package service_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"PR-appointer/internal/service"
+)
+
+func rsaJWK(t *testing.T, kid string, key *rsa.PrivateKey) map[string]string {
+	t.Helper()
+
+	jwk := map[string]string{
+		"kty": "RSA",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+	if kid != "" {
+		jwk["kid"] = kid
+	}
+	return jwk
+}
+
+func signToken(t *testing.T, kid string, key *rsa.PrivateKey) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"sub": "alice",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func TestJWTVerifierKeysWithoutKid(t *testing.T) {
+	keys := make([]*rsa.PrivateKey, 4)
+	for i := range keys {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatalf("generate key: %v", err)
+		}
+		keys[i] = key
+	}
+
+	// Два ключа без kid не должны затирать друг друга; keys[3] в наборе нет
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{
+		rsaJWK(t, "", keys[0]),
+		rsaJWK(t, "", keys[1]),
+		rsaJWK(t, "main", keys[2]),
+	}})
+	if err != nil {
+		t.Fatalf("marshal jwks: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatalf("write jwks: %v", err)
+	}
+
+	verifier, err := service.NewJWTVerifier(service.JWTConfig{KeysFile: path, UsernameClaim: "sub"})
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		kid   string
+		key   *rsa.PrivateKey
+		valid bool
+	}{
+		{"first key without kid", "", keys[0], true},
+		{"second key without kid", "", keys[1], true},
+		{"key with kid", "main", keys[2], true},
+		{"key with kid, token without kid", "", keys[2], true},
+		{"unknown kid, key without kid", "rotated", keys[1], true},
+		{"known kid, other key", "main", keys[0], false},
+		{"unknown key", "", keys[3], false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			username, _, err := verifier.Verify(signToken(t, tt.kid, tt.key))
+			switch {
+			case tt.valid && err != nil:
+				t.Errorf("verify: %v", err)
+			case tt.valid && username != "alice":
+				t.Errorf("username %q, want alice", username)
+			case !tt.valid && err == nil:
+				t.Error("token verified, want error")
+			}
+		})
+	}
+}
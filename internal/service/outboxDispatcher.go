@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+)
+
+const (
+	outboxInterval  = time.Second
+	outboxBatchSize = 100
+	// outboxLease - через сколько событие, взятое упавшим процессом, обработается снова
+	outboxLease = time.Minute
+	// После maxOutboxAttempts неудачных попыток событие больше не повторяется
+	maxOutboxAttempts = 8
+)
+
+// EventSink - приемник доменных событий из outbox.
+// Событие доставляется хотя бы один раз, поэтому Handle должен спокойно переносить повторы.
+type EventSink interface {
+	Name() string
+	Handle(ctx context.Context, event *entity.OutboxEvent) error
+}
+
+// OutboxDispatcher передает события из outbox всем приемникам.
+// Событие считается обработанным, когда его приняли все приемники; иначе оно повторяется целиком.
+type OutboxDispatcher struct {
+	outboxRepo repository.OutboxRepository
+	sinks      []EventSink
+}
+
+func NewOutboxDispatcher(store *repository.Store, sinks ...EventSink) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		outboxRepo: store.Outbox,
+		sinks:      sinks,
+	}
+}
+
+// Run обрабатывает outbox, пока не отменен ctx.
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(outboxInterval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatchDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *OutboxDispatcher) dispatchDue(ctx context.Context) {
+	events, err := d.outboxRepo.ClaimDue(ctx, outboxBatchSize, outboxLease)
+	if err != nil {
+		slog.Error("Error claiming outbox events", "err", err.Error())
+		return
+	}
+
+	for _, event := range events {
+		if err := d.handle(ctx, &event); err != nil {
+			if event.Attempts+1 >= maxOutboxAttempts {
+				slog.Error("Outbox event failed", "event_id", event.EventID, "attempts", event.Attempts+1, "err", err.Error())
+				if err := d.outboxRepo.MarkFailed(ctx, event.ID, err.Error()); err != nil {
+					slog.Error("Error marking outbox event failed", "event_id", event.EventID, "err", err.Error())
+				}
+				continue
+			}
+
+			slog.Warn("Outbox event not handled", "event_id", event.EventID, "attempt", event.Attempts+1, "err", err.Error())
+			if err := d.outboxRepo.Reschedule(ctx, event.ID, retryDelay(event.Attempts+1), err.Error()); err != nil {
+				slog.Error("Error rescheduling outbox event", "event_id", event.EventID, "err", err.Error())
+			}
+			continue
+		}
+
+		if err := d.outboxRepo.MarkProcessed(ctx, event.ID); err != nil {
+			slog.Error("Error marking outbox event processed", "event_id", event.EventID, "err", err.Error())
+		}
+	}
+}
+
+func (d *OutboxDispatcher) handle(ctx context.Context, event *entity.OutboxEvent) error {
+	var failed []string
+	for _, sink := range d.sinks {
+		if err := sink.Handle(ctx, event); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", sink.Name(), err.Error()))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("sinks failed: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+	"PR-appointer/internal/storage/storagetest"
+)
+
+// failingSink не принимает ни одного события и считает попытки.
+type failingSink struct {
+	calls int
+}
+
+func (s *failingSink) Name() string {
+	return "failing"
+}
+
+func (s *failingSink) Handle(ctx context.Context, event *entity.OutboxEvent) error {
+	s.calls++
+	return errors.New("sink is down")
+}
+
+func TestOutboxDispatcherStopsAfterMaxAttempts(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		ctx := context.Background()
+		for _, eventID := range []string{"exhausted", "fresh"} {
+			if err := store.Outbox.Add(ctx, eventID, entity.EventPRCreated, []byte(`{}`)); err != nil {
+				t.Fatalf("add event: %v", err)
+			}
+		}
+
+		// Первое событие уже исчерпало все попытки, кроме последней
+		exhausted, fresh := 1, 2
+		for range maxOutboxAttempts - 1 {
+			if err := store.Outbox.Reschedule(ctx, exhausted, 0, "sink is down"); err != nil {
+				t.Fatalf("reschedule: %v", err)
+			}
+		}
+
+		sink := &failingSink{}
+		NewOutboxDispatcher(store, sink).dispatchDue(ctx)
+		if sink.calls != 2 {
+			t.Fatalf("sink called %d times, want 2", sink.calls)
+		}
+
+		// Отложенное событие снова в очереди, проваленное - нет, как бы его ни откладывали
+		for _, id := range []int{exhausted, fresh} {
+			if err := store.Outbox.Reschedule(ctx, id, 0, "sink is down"); err != nil {
+				t.Fatalf("reschedule: %v", err)
+			}
+		}
+		events, err := store.Outbox.ClaimDue(ctx, 10, time.Minute)
+		if err != nil {
+			t.Fatalf("claim: %v", err)
+		}
+		if len(events) != 1 || events[0].EventID != "fresh" {
+			t.Errorf("due events %+v, want only fresh", events)
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"PR-appointer/internal/entity"
+)
+
+const (
+	defaultPRListLimit = 50
+	maxPRListLimit     = 200
+)
+
+// ListPRs возвращает страницу PR по фильтрам запроса, по умолчанию новые первыми.
+// Курсор следующей страницы хранит сортировку и позицию последнего PR текущей, поэтому страницы
+// не сдвигаются ни при создании новых PR, ни при изменении последнего PR между запросами.
+func (s *PRService) ListPRs(ctx context.Context, req *entity.PRListRequest) (*entity.PRListResponse, error) {
+	switch req.Status {
+	case "", entity.StatusDraft, entity.StatusOpen, entity.StatusMerged, entity.StatusClosed:
+	default:
+		return nil, errors.New("unknown PR status")
+	}
+
+	if !validRange(req.CreatedFrom, req.CreatedTo) || !validRange(req.MergedFrom, req.MergedTo) {
+		return nil, errors.New("from must be before to")
+	}
+
+	filter := entity.PRListFilter{
+		Status:      req.Status,
+		AuthorID:    req.AuthorID,
+		ReviewerID:  req.ReviewerID,
+		Title:       req.Title,
+		CreatedFrom: inUTC(req.CreatedFrom),
+		CreatedTo:   inUTC(req.CreatedTo),
+		MergedFrom:  inUTC(req.MergedFrom),
+		MergedTo:    inUTC(req.MergedTo),
+		Sort:        req.Sort,
+		Limit:       req.Limit,
+	}
+
+	switch filter.Sort {
+	case "":
+		filter.Sort = entity.PRSortCreatedAt
+	case entity.PRSortCreatedAt, entity.PRSortUpdatedAt, entity.PRSortTitle, entity.PRSortID:
+	default:
+		return nil, errors.New("unknown sort field")
+	}
+
+	order := req.Order
+	switch order {
+	case "", "desc":
+		order = "desc"
+		filter.Desc = true
+	case "asc":
+	default:
+		return nil, errors.New("order must be asc or desc")
+	}
+
+	if req.Cursor != "" {
+		after, err := decodeCursor(req.Cursor, filter.Sort, order)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = after
+	}
+
+	if req.TeamName != "" {
+		team, err := s.teamRepo.GetByName(ctx, req.TeamName)
+		if err != nil {
+			return nil, err
+		}
+		filter.TeamID = team.ID
+	}
+
+	switch {
+	case filter.Limit <= 0:
+		filter.Limit = defaultPRListLimit
+	case filter.Limit > maxPRListLimit:
+		filter.Limit = maxPRListLimit
+	}
+
+	// Лишний PR показывает, что за страницей есть следующая
+	limit := filter.Limit
+	filter.Limit++
+	prs, err := s.prRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &entity.PRListResponse{PullRequests: prs}
+	if len(prs) > limit {
+		response.PullRequests = prs[:limit]
+		response.NextCursor = encodeCursor(filter.Sort, order, &prs[limit-1])
+	}
+
+	return response, nil
+}
+
+func validRange(from, to *time.Time) bool {
+	return from == nil || to == nil || from.Before(*to)
+}
+
+// inUTC приводит границу интервала к UTC: в запросе она может прийти с любым смещением (+03:00),
+// а время PR хранится в UTC.
+func inUTC(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// prListCursor - содержимое курсора списка PR. Курсор действует только с той сортировкой,
+// для которой выдан: значение другого поля не задает позицию в списке.
+type prListCursor struct {
+	Sort  string `json:"sort"`
+	Order string `json:"order"`
+	Value string `json:"value,omitempty"`
+	ID    int    `json:"id"`
+}
+
+func encodeCursor(sort, order string, last *entity.PRListItem) string {
+	cursor := prListCursor{Sort: sort, Order: order, ID: last.PullRequestID}
+	switch sort {
+	case entity.PRSortCreatedAt:
+		cursor.Value = last.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case entity.PRSortUpdatedAt:
+		cursor.Value = last.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	case entity.PRSortTitle:
+		cursor.Value = last.PullRequestName
+	}
+
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(encoded, sort, order string) (*entity.PRListCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	var cursor prListCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID <= 0 {
+		return nil, errors.New("invalid cursor")
+	}
+	if cursor.Sort != sort || cursor.Order != order {
+		return nil, errors.New("cursor does not match sort and order")
+	}
+
+	after := &entity.PRListCursor{ID: cursor.ID}
+	switch sort {
+	case entity.PRSortCreatedAt, entity.PRSortUpdatedAt:
+		if after.Time, err = time.Parse(time.RFC3339Nano, cursor.Value); err != nil {
+			return nil, errors.New("invalid cursor")
+		}
+	case entity.PRSortTitle:
+		after.Title = cursor.Value
+	}
+
+	return after, nil
+}
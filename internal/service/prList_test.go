@@ -0,0 +1,181 @@
+package service_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+	"PR-appointer/internal/storage/storagetest"
+)
+
+// listAll проходит все страницы списка и возвращает id PR в порядке выдачи.
+func (f *fixture) listAll(req entity.PRListRequest) []int {
+	f.t.Helper()
+
+	var ids []int
+	for {
+		page, err := f.prs.ListPRs(f.ctx, &req)
+		if err != nil {
+			f.t.Fatalf("list %s %s: %v", req.Sort, req.Order, err)
+		}
+		for _, pr := range page.PullRequests {
+			ids = append(ids, pr.PullRequestID)
+		}
+		if page.NextCursor == "" {
+			return ids
+		}
+		req.Cursor = page.NextCursor
+	}
+}
+
+// createPRs создает PR с названиями в порядке, обратном id, чтобы сортировки различались.
+func (f *fixture) createPRs(n int) {
+	f.t.Helper()
+
+	for id := 1; id <= n; id++ {
+		_, err := f.prs.CreatePR(f.ctx, &entity.PRCreateRequest{
+			PullRequestID:   id,
+			PullRequestName: string(rune('a' + n - id)),
+			AuthorID:        f.author(),
+		})
+		if err != nil {
+			f.t.Fatalf("create PR %d: %v", id, err)
+		}
+		// Разные created_at при миллисекундной точности SQLite
+		time.Sleep(2 * time.Millisecond)
+	}
+}
+
+func TestListPRsPagination(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		f := newFixture(t, store, 5)
+		f.createPRs(5)
+
+		tests := []struct {
+			sort  string
+			order string
+			want  []int
+		}{
+			{"", "", []int{5, 4, 3, 2, 1}},
+			{entity.PRSortCreatedAt, "asc", []int{1, 2, 3, 4, 5}},
+			{entity.PRSortUpdatedAt, "desc", []int{5, 4, 3, 2, 1}},
+			{entity.PRSortTitle, "asc", []int{5, 4, 3, 2, 1}},
+			{entity.PRSortTitle, "desc", []int{1, 2, 3, 4, 5}},
+			{entity.PRSortID, "asc", []int{1, 2, 3, 4, 5}},
+			{entity.PRSortID, "desc", []int{5, 4, 3, 2, 1}},
+		}
+		for _, tt := range tests {
+			got := f.listAll(entity.PRListRequest{Sort: tt.sort, Order: tt.order, Limit: 2})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("sort %q order %q: got %v, want %v", tt.sort, tt.order, got, tt.want)
+			}
+		}
+	})
+}
+
+func TestListPRsCursorSurvivesUpdate(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		f := newFixture(t, store, 5)
+		f.createPRs(5)
+
+		req := entity.PRListRequest{Sort: entity.PRSortUpdatedAt, Limit: 2}
+		first, err := f.prs.ListPRs(f.ctx, &req)
+		if err != nil {
+			t.Fatalf("list first page: %v", err)
+		}
+
+		// Последний PR страницы уходит в начало списка, но следующая страница продолжается с его прежнего места
+		if _, err := f.prs.ClosePR(f.ctx, first.PullRequests[1].PullRequestID); err != nil {
+			t.Fatalf("close: %v", err)
+		}
+
+		req.Cursor = first.NextCursor
+		second, err := f.prs.ListPRs(f.ctx, &req)
+		if err != nil {
+			t.Fatalf("list second page: %v", err)
+		}
+
+		var got []int
+		for _, pr := range second.PullRequests {
+			got = append(got, pr.PullRequestID)
+		}
+		if want := []int{3, 2}; !slices.Equal(got, want) {
+			t.Errorf("second page %v, want %v", got, want)
+		}
+	})
+}
+
+func TestListPRsTimeOffset(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		f := newFixture(t, store, 5)
+		f.createPRs(3)
+		if _, err := f.prs.MergePR(f.ctx, 2); err != nil {
+			t.Fatalf("merge: %v", err)
+		}
+
+		page, err := f.prs.ListPRs(f.ctx, &entity.PRListRequest{Sort: entity.PRSortID, Order: "asc"})
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		second := page.PullRequests[1]
+
+		// Границы с ненулевым смещением задают тот же момент, что и в UTC
+		moscow := time.FixedZone("+03:00", 3*60*60)
+		createdAt := second.CreatedAt.In(moscow)
+		mergedAt := second.MergedAt.In(moscow)
+		mergedEnd := mergedAt.Add(time.Millisecond)
+
+		tests := []struct {
+			name string
+			req  entity.PRListRequest
+			want []int
+		}{
+			{"created_from", entity.PRListRequest{CreatedFrom: &createdAt}, []int{3, 2}},
+			{"created_to", entity.PRListRequest{CreatedTo: &createdAt}, []int{1}},
+			{"merged", entity.PRListRequest{MergedFrom: &mergedAt, MergedTo: &mergedEnd}, []int{2}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if got := f.listAll(tt.req); !slices.Equal(got, tt.want) {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func TestListPRsCursorValidation(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		f := newFixture(t, store, 5)
+		f.createPRs(3)
+
+		page, err := f.prs.ListPRs(f.ctx, &entity.PRListRequest{Sort: entity.PRSortCreatedAt, Order: "desc", Limit: 1})
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+
+		tests := []struct {
+			name string
+			req  entity.PRListRequest
+			want string
+		}{
+			{"other sort", entity.PRListRequest{Sort: entity.PRSortTitle, Order: "desc", Cursor: page.NextCursor}, "cursor does not match sort and order"},
+			{"other order", entity.PRListRequest{Sort: entity.PRSortCreatedAt, Order: "asc", Cursor: page.NextCursor}, "cursor does not match sort and order"},
+			{"not base64", entity.PRListRequest{Cursor: "!!!"}, "invalid cursor"},
+			{"bare id", entity.PRListRequest{Cursor: "Mw"}, "invalid cursor"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := f.prs.ListPRs(f.ctx, &tt.req)
+				expectError(t, err, tt.want)
+			})
+		}
+
+		// Значения по умолчанию совпадают с явными created_at и desc
+		if _, err := f.prs.ListPRs(f.ctx, &entity.PRListRequest{Cursor: page.NextCursor}); err != nil {
+			t.Errorf("cursor with default sort: %v", err)
+		}
+	})
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"errors"
+	"slices"
+
+	"PR-appointer/internal/entity"
+)
+
+// prTransitions - допустимые переходы статусов PR.
+// MERGED - конечное состояние, CLOSED можно только переоткрыть.
+var prTransitions = map[string][]string{
+	entity.StatusDraft:  {entity.StatusOpen, entity.StatusClosed},
+	entity.StatusOpen:   {entity.StatusMerged, entity.StatusClosed},
+	entity.StatusClosed: {entity.StatusOpen},
+	entity.StatusMerged: {},
+}
+
+func checkTransition(from, to string) error {
+	if !slices.Contains(prTransitions[from], to) {
+		return errors.New("invalid PR status transition")
+	}
+	return nil
+}
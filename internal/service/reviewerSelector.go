@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+)
+
+// ReviewerSelector выбирает до n ревьюверов из списка кандидатов команды.
+// Вся случайность выбора берется из rng, чтобы выбор воспроизводился по зерну.
+type ReviewerSelector interface {
+	Select(ctx context.Context, teamID int, candidates []entity.UserResponse, n int, rng *rand.Rand) ([]entity.UserResponse, error)
+}
+
+func newReviewerSelectors(prRepo repository.PRRepository, teamRepo repository.TeamRepository) map[string]ReviewerSelector {
+	return map[string]ReviewerSelector{
+		entity.StrategyRandom:      &randomSelector{},
+		entity.StrategyRoundRobin:  &roundRobinSelector{prRepo: prRepo},
+		entity.StrategyLeastLoaded: &leastLoadedSelector{prRepo: prRepo},
+		entity.StrategyWeighted:    &weightedSelector{teamRepo: teamRepo},
+	}
+}
+
+func isKnownStrategy(strategy string) bool {
+	switch strategy {
+	case entity.StrategyRandom, entity.StrategyRoundRobin, entity.StrategyLeastLoaded, entity.StrategyWeighted:
+		return true
+	}
+	return false
+}
+
+// randomSelector - случайный выбор без учета истории назначений.
+type randomSelector struct{}
+
+func (s *randomSelector) Select(_ context.Context, _ int, candidates []entity.UserResponse, n int, rng *rand.Rand) ([]entity.UserResponse, error) {
+	shuffled := make([]entity.UserResponse, len(candidates))
+	copy(shuffled, candidates)
+
+	rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	return shuffled[:min(n, len(shuffled))], nil
+}
+
+// roundRobinSelector - по очереди: первыми идут те, кого назначали давнее всего.
+type roundRobinSelector struct {
+	prRepo repository.PRRepository
+}
+
+func (s *roundRobinSelector) Select(ctx context.Context, _ int, candidates []entity.UserResponse, n int, _ *rand.Rand) ([]entity.UserResponse, error) {
+	ids := make([]int, 0, len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.UserID)
+	}
+
+	lastAssigned, err := s.prRepo.GetLastAssignedAt(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := make([]entity.UserResponse, len(candidates))
+	copy(ordered, candidates)
+
+	// Никогда не назначавшиеся имеют нулевое время и оказываются в начале очереди
+	sort.SliceStable(ordered, func(i, j int) bool {
+		ti, tj := lastAssigned[ordered[i].UserID], lastAssigned[ordered[j].UserID]
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return ordered[i].UserID < ordered[j].UserID
+	})
+
+	return ordered[:min(n, len(ordered))], nil
+}
+
+// leastLoadedSelector - первыми идут кандидаты с наименьшим числом OPEN PR на ревью.
+type leastLoadedSelector struct {
+	prRepo repository.PRRepository
+}
+
+func (s *leastLoadedSelector) Select(ctx context.Context, _ int, candidates []entity.UserResponse, n int, rng *rand.Rand) ([]entity.UserResponse, error) {
+	ids := make([]int, 0, len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.UserID)
+	}
+
+	openCounts, err := s.prRepo.GetOpenReviewCounts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := make([]entity.UserResponse, len(candidates))
+	copy(ordered, candidates)
+
+	// Перемешиваем, чтобы при равной нагрузке выбор был случайным
+	rng.Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return openCounts[ordered[i].UserID] < openCounts[ordered[j].UserID]
+	})
+
+	return ordered[:min(n, len(ordered))], nil
+}
+
+// weightedSelector - случайный выбор с учетом review_weight участника команды.
+type weightedSelector struct {
+	teamRepo repository.TeamRepository
+}
+
+func (s *weightedSelector) Select(ctx context.Context, teamID int, candidates []entity.UserResponse, n int, rng *rand.Rand) ([]entity.UserResponse, error) {
+	weights, err := s.teamRepo.GetMemberWeights(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Взвешенная выборка без повторений (Efraimidis-Spirakis): ключ u^(1/w)
+	type keyed struct {
+		user entity.UserResponse
+		key  float64
+	}
+	keys := make([]keyed, 0, len(candidates))
+	for _, c := range candidates {
+		// Кандидаты из других команд автора не имеют веса в этой команде
+		weight, ok := weights[c.UserID]
+		if !ok {
+			weight = 1
+		}
+		if weight <= 0 {
+			continue
+		}
+		keys = append(keys, keyed{
+			user: c,
+			key:  math.Pow(rng.Float64(), 1/float64(weight)),
+		})
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].key > keys[j].key
+	})
+
+	var selected []entity.UserResponse
+	for i := 0; i < min(n, len(keys)); i++ {
+		selected = append(selected, keys[i].user)
+	}
+
+	return selected, nil
+}
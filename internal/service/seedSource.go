@@ -0,0 +1,49 @@
+package service
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// SeedConfig задает зерно случайности при выборе ревьюверов.
+type SeedConfig struct {
+	// Seed - базовое зерно; 0 - последовательность зерен не воспроизводится между запусками
+	Seed int64
+	// PerPR - зерно выбора равно Seed + id PR: повторный выбор для PR с теми же кандидатами дает тот же результат
+	PerPR bool
+}
+
+// SeedSource выдает зерно для каждого выбора ревьюверов. Зерно пишется в объяснение
+// выбора и в журнал аудита, поэтому любое назначение можно воспроизвести.
+type SeedSource struct {
+	perPR bool
+	base  int64
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func NewSeedSource(cfg SeedConfig) *SeedSource {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = rand.Int63()
+	}
+
+	return &SeedSource{
+		perPR: cfg.PerPR,
+		base:  cfg.Seed,
+		rng:   rand.New(rand.NewSource(seed)),
+	}
+}
+
+// next возвращает зерно для выбора ревьюверов PR prID.
+func (s *SeedSource) next(prID int) int64 {
+	if s.perPR {
+		return s.base + int64(prID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rng.Int63()
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+)
+
+// defaultDeliveriesLimit - сколько последних доставок показывать, если лимит не задан
+const defaultDeliveriesLimit = 50
+
+type SubscriptionService struct {
+	subscriptionRepo repository.SubscriptionRepository
+	deliveryRepo     repository.DeliveryRepository
+}
+
+func NewSubscriptionService(store *repository.Store) *SubscriptionService {
+	return &SubscriptionService{
+		subscriptionRepo: store.Subscriptions,
+		deliveryRepo:     store.Deliveries,
+	}
+}
+
+// Create подписывает url на события events; пустой список - подписка на все события.
+func (s *SubscriptionService) Create(ctx context.Context, req *entity.SubscriptionCreateRequest) (*entity.WebhookSubscription, error) {
+	target, err := url.Parse(req.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, errors.New("invalid subscription url")
+	}
+
+	for _, eventType := range req.Events {
+		if !isKnownEvent(eventType) {
+			return nil, errors.New("unknown event type")
+		}
+	}
+
+	events := req.Events
+	if events == nil {
+		events = []string{}
+	}
+
+	return s.subscriptionRepo.Create(ctx, req.URL, req.Secret, events)
+}
+
+func (s *SubscriptionService) List(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	return s.subscriptionRepo.List(ctx)
+}
+
+// Delete удаляет подписку вместе с журналом ее доставок.
+func (s *SubscriptionService) Delete(ctx context.Context, subscriptionID int) error {
+	return s.subscriptionRepo.Delete(ctx, subscriptionID)
+}
+
+// ListDeliveries возвращает последние доставки, новые первыми.
+func (s *SubscriptionService) ListDeliveries(ctx context.Context, filter entity.DeliveryFilter) ([]entity.WebhookDelivery, error) {
+	switch filter.Status {
+	case "", entity.DeliveryPending, entity.DeliverySucceeded, entity.DeliveryFailed:
+	default:
+		return nil, errors.New("unknown delivery status")
+	}
+
+	if filter.SubscriptionID != 0 {
+		if _, err := s.subscriptionRepo.GetByID(ctx, filter.SubscriptionID); err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.Limit <= 0 || filter.Limit > defaultDeliveriesLimit {
+		filter.Limit = defaultDeliveriesLimit
+	}
+
+	return s.deliveryRepo.List(ctx, filter)
+}
+
+// Redeliver ставит доставку в очередь заново с обнуленным счетчиком попыток.
+func (s *SubscriptionService) Redeliver(ctx context.Context, deliveryID int) error {
+	return s.deliveryRepo.Redeliver(ctx, deliveryID)
+}
@@ -4,44 +4,115 @@ import (
 	"context"
 	"errors"
 
-	"github.com/jackc/pgx/v5/pgxpool"
-
 	"PR-appointer/internal/entity"
 	"PR-appointer/internal/repository"
 )
 
 type TeamService struct {
-	teamRepo *repository.TeamRepository
-	userRepo *repository.UserRepository
+	teamRepo repository.TeamRepository
+	userRepo repository.UserRepository
+	uow      repository.UnitOfWork
+	audit    *auditLog
 }
 
-func NewTeamService(db *pgxpool.Pool) *TeamService {
+func NewTeamService(store *repository.Store) *TeamService {
 	return &TeamService{
-		teamRepo: repository.NewTeamRepository(db),
-		userRepo: repository.NewUserRepository(db),
+		teamRepo: store.Teams,
+		userRepo: store.Users,
+		uow:      store.UoW,
+		audit:    newAuditLog(store),
 	}
 }
 
 func (s *TeamService) CreateTeam(ctx context.Context, req *entity.TeamCreateRequest) (*entity.TeamResponse, error) {
+	// Команда, ее настройки и участники создаются атомарно
+	return inTx(ctx, s.uow, func(ctx context.Context) (*entity.TeamResponse, error) {
+		return s.createTeam(ctx, req)
+	})
+}
+
+func (s *TeamService) createTeam(ctx context.Context, req *entity.TeamCreateRequest) (*entity.TeamResponse, error) {
 	existingTeam, err := s.teamRepo.GetByName(ctx, req.TeamName)
-	if existingTeam != nil || err != nil {
+	if existingTeam != nil {
 		return nil, errors.New("team already exists")
 	}
+	if err != nil && err.Error() != "team not found" {
+		return nil, err
+	}
+
+	strategy := req.ReviewerStrategy
+	if strategy == "" {
+		strategy = entity.StrategyRandom
+	}
+	if !isKnownStrategy(strategy) {
+		return nil, errors.New("unknown reviewer strategy")
+	}
 
 	team, err := s.teamRepo.Create(ctx, req.TeamName)
 	if err != nil {
 		return nil, err
 	}
 
+	settings, err := s.teamRepo.UpsertSettings(ctx, &entity.TeamSettings{
+		TeamID:           team.ID,
+		ReviewerStrategy: strategy,
+		ReviewersPerPR:   entity.DefaultReviewersPerPR,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	// Создаем/обновляем пользователей и добавляем в команду
 	var members []entity.UserResponse
 	for _, member := range req.Members {
+		if member.ReviewWeight < 0 {
+			return nil, errors.New("review_weight must not be negative")
+		}
+
+		previous, err := s.userRepo.GetByUsername(ctx, member.Username)
+		if err != nil {
+			return nil, err
+		}
+
 		user, err := s.userRepo.Upsert(ctx, member.Username, member.IsActive)
 		if err != nil {
 			return nil, err
 		}
 
-		if err := s.teamRepo.AddMember(ctx, team.ID, user.UserID); err != nil {
+		// Upsert меняет статус уже существующего пользователя
+		if previous != nil && previous.IsActive != user.IsActive {
+			err = s.audit.record(ctx, entity.AuditEntry{
+				Action: entity.AuditUserStatusChanged,
+				UserID: user.UserID,
+				TeamID: team.ID,
+				Reason: "updated with team " + team.Name,
+			}, userState(previous.IsActive), userState(user.IsActive))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		weight := member.ReviewWeight
+		if weight == 0 {
+			weight = 1
+		}
+
+		if err := s.teamRepo.AddMember(ctx, team.ID, user.UserID, weight); err != nil {
+			return nil, err
+		}
+
+		if member.IsPrimary {
+			if err := s.teamRepo.SetPrimaryTeam(ctx, user.UserID, team.ID); err != nil {
+				return nil, err
+			}
+		}
+
+		err = s.audit.record(ctx, entity.AuditEntry{
+			Action: entity.AuditTeamMemberAdded,
+			UserID: user.UserID,
+			TeamID: team.ID,
+		}, nil, map[string]any{"review_weight": weight, "is_primary": member.IsPrimary})
+		if err != nil {
 			return nil, err
 		}
 
@@ -49,13 +120,14 @@ func (s *TeamService) CreateTeam(ctx context.Context, req *entity.TeamCreateRequ
 			UserID:   user.UserID,
 			Username: user.Username,
 			IsActive: user.IsActive,
-			TeamName: user.TeamName,
+			TeamName: team.Name,
 		})
 	}
 
 	return &entity.TeamResponse{
-		TeamName: team.Name,
-		Members:  members,
+		TeamName:         team.Name,
+		ReviewerStrategy: settings.ReviewerStrategy,
+		Members:          members,
 	}, nil
 }
 
@@ -70,6 +142,11 @@ func (s *TeamService) GetTeamByName(ctx context.Context, teamName string) (*enti
 		return nil, err
 	}
 
+	settings, err := s.teamRepo.GetSettings(ctx, team.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	var memberResponses []entity.UserResponse
 	for _, member := range members {
 		memberResponses = append(memberResponses, entity.UserResponse{
@@ -81,7 +158,163 @@ func (s *TeamService) GetTeamByName(ctx context.Context, teamName string) (*enti
 	}
 
 	return &entity.TeamResponse{
-		TeamName: team.Name,
-		Members:  memberResponses,
+		TeamName:         team.Name,
+		ReviewerStrategy: settings.ReviewerStrategy,
+		Members:          memberResponses,
 	}, nil
 }
+
+// IsMember сообщает, состоит ли пользователь в команде.
+func (s *TeamService) IsMember(ctx context.Context, teamName string, userID int) (bool, error) {
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		return false, err
+	}
+
+	members, err := s.teamRepo.GetMembers(ctx, team.ID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, member := range members {
+		if member.UserID == userID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (s *TeamService) GetSettings(ctx context.Context, teamName string) (*entity.TeamSettingsResponse, error) {
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.teamRepo.GetSettings(ctx, team.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	fallbackTeams, err := s.teamRepo.GetFallbackTeams(ctx, team.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toTeamSettingsResponse(team, settings, fallbackTeams), nil
+}
+
+func (s *TeamService) UpdateSettings(ctx context.Context, req *entity.TeamSettingsRequest) (*entity.TeamSettingsResponse, error) {
+	return inTx(ctx, s.uow, func(ctx context.Context) (*entity.TeamSettingsResponse, error) {
+		return s.updateSettings(ctx, req)
+	})
+}
+
+func (s *TeamService) updateSettings(ctx context.Context, req *entity.TeamSettingsRequest) (*entity.TeamSettingsResponse, error) {
+	team, err := s.teamRepo.GetByName(ctx, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.teamRepo.GetSettings(ctx, team.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Обновляем только переданные поля
+	if req.ReviewerStrategy != nil {
+		settings.ReviewerStrategy = *req.ReviewerStrategy
+	}
+	if req.ReviewersPerPR != nil {
+		settings.ReviewersPerPR = *req.ReviewersPerPR
+	}
+	if req.MinReviewers != nil {
+		settings.MinReviewers = *req.MinReviewers
+	}
+	if req.FailOnInsufficient != nil {
+		settings.FailOnInsufficient = *req.FailOnInsufficient
+	}
+	if req.RequiredApprovals != nil {
+		settings.RequiredApprovals = *req.RequiredApprovals
+	}
+	if req.MaxOpenReviews != nil {
+		settings.MaxOpenReviews = *req.MaxOpenReviews
+	}
+
+	if !isKnownStrategy(settings.ReviewerStrategy) {
+		return nil, errors.New("unknown reviewer strategy")
+	}
+	if settings.ReviewersPerPR < 1 {
+		return nil, errors.New("reviewers_per_pr must be at least 1")
+	}
+	if settings.MinReviewers < 0 || settings.MinReviewers > settings.ReviewersPerPR {
+		return nil, errors.New("min_reviewers must be between 0 and reviewers_per_pr")
+	}
+	if settings.RequiredApprovals < 0 || settings.RequiredApprovals > settings.ReviewersPerPR {
+		return nil, errors.New("required_approvals must be between 0 and reviewers_per_pr")
+	}
+	if settings.MaxOpenReviews < 0 {
+		return nil, errors.New("max_open_reviews must be at least 0")
+	}
+
+	// Проверяем резервные команды до сохранения настроек
+	var fallbackTeams []entity.Team
+	if req.FallbackTeams != nil {
+		seen := make(map[int]bool)
+		for _, name := range *req.FallbackTeams {
+			fallbackTeam, err := s.teamRepo.GetByName(ctx, name)
+			if err != nil {
+				return nil, errors.New("fallback team not found")
+			}
+			if fallbackTeam.ID == team.ID {
+				return nil, errors.New("team cannot be its own fallback")
+			}
+			if seen[fallbackTeam.ID] {
+				continue
+			}
+			seen[fallbackTeam.ID] = true
+			fallbackTeams = append(fallbackTeams, *fallbackTeam)
+		}
+	}
+
+	settings, err = s.teamRepo.UpsertSettings(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.FallbackTeams != nil {
+		fallbackTeamIDs := make([]int, 0, len(fallbackTeams))
+		for _, fallbackTeam := range fallbackTeams {
+			fallbackTeamIDs = append(fallbackTeamIDs, fallbackTeam.ID)
+		}
+
+		if err := s.teamRepo.SetFallbackTeams(ctx, team.ID, fallbackTeamIDs); err != nil {
+			return nil, err
+		}
+	} else {
+		fallbackTeams, err = s.teamRepo.GetFallbackTeams(ctx, team.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return toTeamSettingsResponse(team, settings, fallbackTeams), nil
+}
+
+func toTeamSettingsResponse(team *entity.Team, settings *entity.TeamSettings, fallbackTeams []entity.Team) *entity.TeamSettingsResponse {
+	fallbackNames := make([]string, 0, len(fallbackTeams))
+	for _, fallbackTeam := range fallbackTeams {
+		fallbackNames = append(fallbackNames, fallbackTeam.Name)
+	}
+
+	return &entity.TeamSettingsResponse{
+		TeamName:           team.Name,
+		ReviewerStrategy:   settings.ReviewerStrategy,
+		ReviewersPerPR:     settings.ReviewersPerPR,
+		MinReviewers:       settings.MinReviewers,
+		FailOnInsufficient: settings.FailOnInsufficient,
+		RequiredApprovals:  settings.RequiredApprovals,
+		MaxOpenReviews:     settings.MaxOpenReviews,
+		FallbackTeams:      fallbackNames,
+	}
+}
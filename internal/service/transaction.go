@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"PR-appointer/internal/repository"
+)
+
+// inTx выполняет fn в единице работы и возвращает ее результат:
+// при ошибке все изменения откатываются.
+func inTx[T any](ctx context.Context, uow repository.UnitOfWork, fn func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+	err := uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = fn(ctx)
+		return err
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return result, nil
+}
@@ -2,37 +2,68 @@ package service
 
 import (
 	"context"
-
-	"github.com/jackc/pgx/v5/pgxpool"
+	"errors"
+	"slices"
 
 	"PR-appointer/internal/entity"
 	"PR-appointer/internal/repository"
 )
 
 type UserService struct {
-	UserRepo *repository.UserRepository
-	prRepo   *repository.PRRepository
+	UserRepo  repository.UserRepository
+	prRepo    repository.PRRepository
+	teamRepo  repository.TeamRepository
+	prService *PRService
+	uow       repository.UnitOfWork
+	events    EventPublisher
+	audit     *auditLog
 }
 
-func NewUserService(db *pgxpool.Pool) *UserService {
+func NewUserService(store *repository.Store, seeds *SeedSource) *UserService {
 	return &UserService{
-		UserRepo: repository.NewUserRepository(db),
-		prRepo:   repository.NewPRRepository(db),
+		UserRepo:  store.Users,
+		prRepo:    store.PRs,
+		teamRepo:  store.Teams,
+		prService: NewPRService(store, seeds),
+		uow:       store.UoW,
+		events:    newOutboxPublisher(store),
+		audit:     newAuditLog(store),
 	}
 }
 
 func (s *UserService) SetStatus(ctx context.Context, userID int, isActive bool) (*entity.UserResponse, error) {
-	user, err := s.UserRepo.UpdateStatus(ctx, userID, isActive)
-	if err != nil {
-		return nil, err
-	}
+	// Событие пишется в той же транзакции, что и статус
+	return inTx(ctx, s.uow, func(ctx context.Context) (*entity.UserResponse, error) {
+		previous, err := s.UserRepo.GetByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
 
-	return &entity.UserResponse{
-		UserID:   user.UserID,
-		Username: user.Username,
-		TeamName: user.TeamName,
-		IsActive: user.IsActive,
-	}, nil
+		user, err := s.UserRepo.UpdateStatus(ctx, userID, isActive)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.audit.record(ctx, entity.AuditEntry{
+			Action: entity.AuditUserStatusChanged,
+			UserID: user.UserID,
+		}, userState(previous.IsActive), userState(user.IsActive))
+		if err != nil {
+			return nil, err
+		}
+
+		response := &entity.UserResponse{
+			UserID:   user.UserID,
+			Username: user.Username,
+			TeamName: user.TeamName,
+			IsActive: user.IsActive,
+		}
+		if err := s.events.Publish(ctx, entity.EventUserStatusChanged, response); err != nil {
+			return nil, err
+		}
+
+		return response, nil
+	})
 }
 
 func (s *UserService) GetUserReviews(ctx context.Context, userID int) (*entity.UserReviewsResponse, error) {
@@ -46,19 +77,143 @@ func (s *UserService) GetUserReviews(ctx context.Context, userID int) (*entity.U
 		return nil, err
 	}
 
-	var prSummaries []entity.PRSummary
-	for _, pr := range prs {
-		prSummaries = append(prSummaries, entity.PRSummary{
-			PullRequestID:   pr.ID,
-			PullRequestName: pr.Title,
-			AuthorID:        pr.AuthorID,
-			Status:          string(pr.Status),
-		})
-	}
-
 	return &entity.UserReviewsResponse{
 		UserID:       user.UserID,
 		Username:     user.Username,
-		PullRequests: prSummaries,
+		PullRequests: prs,
 	}, nil
 }
+
+// BulkDeactivate деактивирует пользователей (список и/или всю команду) и в той же транзакции
+// переназначает их места ревьюверов в OPEN PR по обычным правилам выбора.
+func (s *UserService) BulkDeactivate(ctx context.Context, req *entity.BulkDeactivateRequest) (*entity.BulkDeactivateResponse, error) {
+	if len(req.UserIDs) == 0 && req.TeamName == "" {
+		return nil, errors.New("user_ids or team_name is required")
+	}
+
+	response := &entity.BulkDeactivateResponse{
+		Deactivated: []entity.UserResponse{},
+		Reassigned:  []entity.ReassignmentResult{},
+		Unfilled:    []entity.ReassignmentResult{},
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		userIDs := slices.Clone(req.UserIDs)
+		if req.TeamName != "" {
+			team, err := s.teamRepo.GetByName(ctx, req.TeamName)
+			if err != nil {
+				return err
+			}
+
+			members, err := s.teamRepo.GetMembers(ctx, team.ID)
+			if err != nil {
+				return err
+			}
+			for _, member := range members {
+				userIDs = append(userIDs, member.UserID)
+			}
+		}
+
+		slices.Sort(userIDs)
+		userIDs = slices.Compact(userIDs)
+
+		// Прежний статус и команда нужны для журнала аудита и ответа
+		previous := make(map[int]*entity.UserResponse, len(userIDs))
+		for _, userID := range userIDs {
+			user, err := s.UserRepo.GetByID(ctx, userID)
+			if err != nil {
+				return err
+			}
+			previous[userID] = user
+		}
+
+		// Сначала деактивируем, чтобы пользователи не попали в кандидаты на замену
+		users, err := s.UserRepo.SetActiveMany(ctx, userIDs, false)
+		if err != nil {
+			return err
+		}
+		if len(users) != len(userIDs) {
+			return errors.New("user not found")
+		}
+
+		for _, user := range users {
+			deactivated := entity.UserResponse{
+				UserID:   user.ID,
+				Username: user.Username,
+				TeamName: previous[user.ID].TeamName,
+				IsActive: user.IsActive,
+			}
+			if err := s.events.Publish(ctx, entity.EventUserStatusChanged, deactivated); err != nil {
+				return err
+			}
+			err := s.audit.record(ctx, entity.AuditEntry{
+				Action: entity.AuditUserStatusChanged,
+				UserID: user.ID,
+				Reason: "bulk deactivation",
+			}, userState(previous[user.ID].IsActive), userState(user.IsActive))
+			if err != nil {
+				return err
+			}
+			response.Deactivated = append(response.Deactivated, deactivated)
+		}
+
+		assignments, err := s.prRepo.GetOpenAssignments(ctx, userIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, assignment := range assignments {
+			pr, err := s.prRepo.GetByIDForUpdate(ctx, assignment.PullRequestID)
+			if err != nil {
+				return err
+			}
+
+			// Пока ждали блокировку, PR могли закрыть или переназначить ревьювера
+			if pr.Status != entity.StatusOpen {
+				continue
+			}
+			assigned, err := s.prRepo.IsReviewerAssigned(ctx, pr.ID, assignment.ReviewerID)
+			if err != nil {
+				return err
+			}
+			if !assigned {
+				continue
+			}
+
+			result := entity.ReassignmentResult{
+				PullRequestID: assignment.PullRequestID,
+				OldReviewerID: assignment.ReviewerID,
+			}
+
+			newReviewer, _, err := s.prService.replaceReviewer(ctx, pr, assignment.ReviewerID, "reviewer deactivated")
+			if err != nil {
+				if err.Error() != "no active replacement candidate in team" {
+					return err
+				}
+
+				// Замены нет - снимаем неактивного ревьювера, место остается незаполненным
+				if err := s.prService.releaseReviewer(ctx, pr, assignment.ReviewerID, "reviewer deactivated"); err != nil {
+					return err
+				}
+				result.Reason = err.Error()
+				response.Unfilled = append(response.Unfilled, result)
+				continue
+			}
+
+			result.NewReviewer = newReviewer
+			response.Reassigned = append(response.Reassigned, result)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// userState - состояние пользователя, которое пишется в журнал аудита
+func userState(isActive bool) map[string]any {
+	return map[string]any{"is_active": isActive}
+}
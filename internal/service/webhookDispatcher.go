@@ -0,0 +1,134 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+)
+
+const (
+	dispatchInterval  = 5 * time.Second
+	dispatchBatchSize = 20
+	// deliveryLease - на сколько взятая доставка скрыта от других экземпляров сервиса.
+	// Больше таймаута запроса, чтобы доставка не ушла дважды
+	deliveryLease   = 2 * time.Minute
+	deliveryTimeout = 10 * time.Second
+	// После maxDeliveryAttempts неудачных попыток доставка считается проваленной
+	maxDeliveryAttempts = 8
+	retryBaseDelay      = 30 * time.Second
+	retryMaxDelay       = time.Hour
+)
+
+// WebhookDispatcher отправляет доставки из очереди подписчикам
+// и повторяет неудачные с экспоненциально растущей паузой.
+type WebhookDispatcher struct {
+	subscriptionRepo repository.SubscriptionRepository
+	deliveryRepo     repository.DeliveryRepository
+	client           *http.Client
+}
+
+func NewWebhookDispatcher(store *repository.Store) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		subscriptionRepo: store.Subscriptions,
+		deliveryRepo:     store.Deliveries,
+		client:           &http.Client{Timeout: deliveryTimeout},
+	}
+}
+
+// Run отправляет доставки, пока не отменен ctx.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(dispatchInterval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatchDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *WebhookDispatcher) dispatchDue(ctx context.Context) {
+	deliveries, err := d.deliveryRepo.ClaimDue(ctx, dispatchBatchSize, deliveryLease)
+	if err != nil {
+		slog.Error("Error claiming webhook deliveries", "err", err.Error())
+		return
+	}
+
+	for _, delivery := range deliveries {
+		attempt := d.deliver(ctx, &delivery)
+		if !attempt.Succeeded {
+			if delivery.Attempts+1 < maxDeliveryAttempts {
+				attempt.RetryIn = retryDelay(delivery.Attempts + 1)
+			}
+			slog.Warn("Webhook delivery failed", "delivery_id", delivery.ID, "attempt", delivery.Attempts+1, "err", attempt.Error)
+		}
+
+		if err := d.deliveryRepo.RecordAttempt(ctx, delivery.ID, attempt); err != nil {
+			slog.Error("Error recording webhook delivery", "delivery_id", delivery.ID, "err", err.Error())
+		}
+	}
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery *entity.WebhookDelivery) entity.DeliveryAttempt {
+	subscription, err := d.subscriptionRepo.GetByID(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return entity.DeliveryAttempt{Error: err.Error()}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return entity.DeliveryAttempt{Error: err.Error()}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "PR-appointer-webhooks")
+	req.Header.Set("X-Appointer-Event", delivery.EventType)
+	req.Header.Set("X-Appointer-Delivery", strconv.Itoa(delivery.ID))
+	req.Header.Set("X-Appointer-Signature-256", "sha256="+signPayload(subscription.Secret, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return entity.DeliveryAttempt{Error: err.Error()}
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	attempt := entity.DeliveryAttempt{ResponseStatus: resp.StatusCode}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		attempt.Succeeded = true
+	} else {
+		attempt.Error = fmt.Sprintf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return attempt
+}
+
+// retryDelay - пауза после attempts неудачных попыток: 30s, 1m, 2m, ... но не больше часа
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, retryMaxDelay)
+}
+
+// signPayload - HMAC-SHA256 тела на секрете подписки, как X-Hub-Signature-256 у GitHub
+func signPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
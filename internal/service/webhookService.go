@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+)
+
+// maxTitleLength - ограничение pull_requests.title
+const maxTitleLength = 100
+
+// WebhookService применяет события PR из внешних систем через обычные операции PRService.
+type WebhookService struct {
+	prService    *PRService
+	userRepo     repository.UserRepository
+	externalRepo repository.ExternalPRRepository
+	uow          repository.UnitOfWork
+}
+
+func NewWebhookService(store *repository.Store, seeds *SeedSource) *WebhookService {
+	return &WebhookService{
+		prService:    NewPRService(store, seeds),
+		userRepo:     store.Users,
+		externalRepo: store.ExternalPRs,
+		uow:          store.UoW,
+	}
+}
+
+// Apply применяет событие. Повторная доставка не меняет PR: открытие уже известного PR
+// и переход в статус, в котором PR уже находится, возвращаются как проигнорированные.
+func (s *WebhookService) Apply(ctx context.Context, event *entity.ExternalPREvent) (*entity.WebhookResult, error) {
+	// В журнале аудита изменения от вебхука записываются на провайдера
+	ctx = WithActor(ctx, event.Provider)
+	result := &entity.WebhookResult{Action: event.Action}
+
+	if event.Action == entity.ExternalActionOpened {
+		return s.open(ctx, event, result)
+	}
+
+	prID, err := s.externalRepo.GetPRID(ctx, event.Provider, event.Repository, event.Number)
+	if err != nil {
+		return nil, err
+	}
+	result.PullRequestID = prID
+
+	switch event.Action {
+	case entity.ExternalActionReady:
+		err = s.transition(ctx, prID, result, s.prService.MarkReady)
+	case entity.ExternalActionClosed:
+		err = s.transition(ctx, prID, result, s.prService.ClosePR)
+	case entity.ExternalActionReopened:
+		err = s.transition(ctx, prID, result, s.prService.ReopenPR)
+	case entity.ExternalActionMerged:
+		var pr *entity.MergedPRResponse
+		pr, err = s.prService.RecordMerge(ctx, prID)
+		if err == nil {
+			result.Status = pr.Status
+		}
+	default:
+		result.Ignored = "unsupported action"
+	}
+	if err != nil {
+		if err.Error() == "invalid PR status transition" {
+			result.Ignored = "PR is already in this state or cannot move to it"
+			return result, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *WebhookService) transition(ctx context.Context, prID int, result *entity.WebhookResult,
+	fn func(ctx context.Context, prID int) (*entity.PRDetailResponse, error)) error {
+	pr, err := fn(ctx, prID)
+	if err != nil {
+		return err
+	}
+
+	result.Status = pr.Status
+	return nil
+}
+
+// open создает PR сервиса для нового внешнего PR и запоминает связь между ними.
+// Автор ищется по логину во внешней системе, который должен совпадать с users.username.
+func (s *WebhookService) open(ctx context.Context, event *entity.ExternalPREvent, result *entity.WebhookResult) (*entity.WebhookResult, error) {
+	if prID, err := s.externalRepo.GetPRID(ctx, event.Provider, event.Repository, event.Number); err == nil {
+		result.PullRequestID = prID
+		result.Ignored = "PR is already tracked"
+		return result, nil
+	} else if err.Error() != "external PR not found" {
+		return nil, err
+	}
+
+	author, err := s.userRepo.GetByUsername(ctx, event.AuthorLogin)
+	if err != nil {
+		return nil, err
+	}
+	if author == nil {
+		return nil, errors.New("author not found")
+	}
+
+	title := []rune(event.Title)
+	if len(title) > maxTitleLength {
+		title = title[:maxTitleLength]
+	}
+
+	// У внешнего PR нет id сервиса: его выдает хранилище при создании, связь хранится в external_pull_requests
+	pr, err := inTx(ctx, s.uow, func(ctx context.Context) (*entity.PRDetailResponse, error) {
+		pr, err := s.prService.createPR(ctx, &entity.PRCreateRequest{
+			PullRequestName: string(title),
+			AuthorID:        author.ID,
+			Draft:           event.Draft,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.externalRepo.Link(ctx, event.Provider, event.Repository, event.Number, pr.PullRequestID); err != nil {
+			return nil, err
+		}
+		return pr, nil
+	})
+	if err != nil {
+		// Ту же доставку параллельно обработал другой запрос
+		if err.Error() == "external PR already linked" {
+			result.PullRequestID, _ = s.externalRepo.GetPRID(ctx, event.Provider, event.Repository, event.Number)
+			result.Ignored = "PR is already tracked"
+			return result, nil
+		}
+		return nil, err
+	}
+
+	result.PullRequestID = pr.PullRequestID
+	result.Status = pr.Status
+	return result, nil
+}
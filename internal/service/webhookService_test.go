@@ -0,0 +1,67 @@
+package service_test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+	"PR-appointer/internal/service"
+	"PR-appointer/internal/storage/storagetest"
+)
+
+func TestWebhookPRIDs(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, store *repository.Store) {
+		f := newFixture(t, store, 5)
+		webhooks := service.NewWebhookService(store, service.NewSeedSource(service.SeedConfig{Seed: 1}))
+
+		open := func(number int) int {
+			result, err := webhooks.Apply(f.ctx, &entity.ExternalPREvent{
+				Provider:    entity.ProviderGitHub,
+				Repository:  "octo-org/backend",
+				Number:      number,
+				Action:      entity.ExternalActionOpened,
+				Title:       "External PR",
+				AuthorLogin: f.members[0].Username,
+			})
+			if err != nil {
+				t.Errorf("open external PR %d: %v", number, err)
+				return 0
+			}
+			return result.PullRequestID
+		}
+
+		// id внешних PR не совпадают с id, которые выбрали клиенты
+		f.createPR(1, false)
+		f.createPR(3, false)
+		if id := open(1); id != 4 {
+			t.Errorf("external PR got id %d, want 4", id)
+		}
+		f.createPR(5, false)
+		if id := open(2); id != 6 {
+			t.Errorf("external PR got id %d, want 6", id)
+		}
+
+		// Параллельные доставки получают разные id без повторных попыток
+		ids := make([]int, 5)
+		var wg sync.WaitGroup
+		for i := range ids {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ids[i] = open(10 + i)
+			}()
+		}
+		wg.Wait()
+
+		slices.Sort(ids)
+		if want := []int{7, 8, 9, 10, 11}; !slices.Equal(ids, want) {
+			t.Errorf("concurrent external PRs got ids %v, want %v", ids, want)
+		}
+
+		// id, выданный внешнему PR, для клиента занят
+		_, err := f.prs.CreatePR(f.ctx, &entity.PRCreateRequest{PullRequestID: 7, PullRequestName: "Taken", AuthorID: f.author()})
+		expectError(t, err, "PR already exists")
+	})
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+)
+
+// WebhookSink ставит событие в очередь доставки каждой подписке, которая его ждет.
+// Сами запросы отправляет WebhookDispatcher.
+type WebhookSink struct {
+	subscriptionRepo repository.SubscriptionRepository
+	deliveryRepo     repository.DeliveryRepository
+}
+
+func NewWebhookSink(store *repository.Store) *WebhookSink {
+	return &WebhookSink{
+		subscriptionRepo: store.Subscriptions,
+		deliveryRepo:     store.Deliveries,
+	}
+}
+
+func (s *WebhookSink) Name() string {
+	return "webhooks"
+}
+
+// Handle можно вызывать повторно для того же события: доставка подписке не задвоится.
+func (s *WebhookSink) Handle(ctx context.Context, event *entity.OutboxEvent) error {
+	subscriptions, err := s.subscriptionRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		if len(subscription.Events) > 0 && !slices.Contains(subscription.Events, event.EventType) {
+			continue
+		}
+
+		if err := s.deliveryRepo.Enqueue(ctx, subscription.ID, event.EventID, event.EventType, event.Payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
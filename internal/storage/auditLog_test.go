@@ -0,0 +1,87 @@
+package storage_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"PR-appointer/config"
+	"PR-appointer/internal/entity"
+	"PR-appointer/internal/repository"
+	"PR-appointer/internal/storage"
+	"PR-appointer/internal/storage/storagetest"
+)
+
+func TestAuditLogAppendOnly(t *testing.T) {
+	ctx := context.Background()
+
+	// Каждый драйвер дает хранилище и выполнение произвольного запроса в обход репозиториев
+	drivers := map[string]func(t *testing.T) (*repository.Store, func(query string) error){
+		storage.DriverSQLite: func(t *testing.T) (*repository.Store, func(query string) error) {
+			path := filepath.Join(t.TempDir(), "test.db")
+			cfg := &config.Config{Env: config.Env{DBDriver: storage.DriverSQLite, DBPath: path}}
+			store, err := storage.NewStore(ctx, cfg)
+			if err != nil {
+				t.Fatalf("open store: %v", err)
+			}
+
+			db, err := sql.Open("sqlite3", path)
+			if err != nil {
+				t.Fatalf("open sqlite: %v", err)
+			}
+			t.Cleanup(func() { _ = db.Close() })
+
+			return store, func(query string) error {
+				_, err := db.ExecContext(ctx, query)
+				return err
+			}
+		},
+		storage.DriverPostgres: func(t *testing.T) (*repository.Store, func(query string) error) {
+			store := storagetest.Open(t, storage.DriverPostgres)
+
+			pool, err := pgxpool.New(ctx, os.Getenv(storagetest.PostgresDSNEnv))
+			if err != nil {
+				t.Fatalf("open postgres: %v", err)
+			}
+			t.Cleanup(pool.Close)
+
+			return store, func(query string) error {
+				_, err := pool.Exec(ctx, query)
+				return err
+			}
+		},
+	}
+
+	for _, driver := range []string{storage.DriverSQLite, storage.DriverPostgres} {
+		t.Run(driver, func(t *testing.T) {
+			store, exec := drivers[driver](t)
+
+			err := store.Audit.Add(ctx, &entity.AuditEntry{Actor: "test", Action: entity.AuditPRCreated, PullRequestID: 1})
+			if err != nil {
+				t.Fatalf("add entry: %v", err)
+			}
+
+			for _, query := range []string{
+				`UPDATE audit_log SET actor = 'someone else'`,
+				`DELETE FROM audit_log`,
+			} {
+				if err := exec(query); err == nil || !strings.Contains(err.Error(), "append-only") {
+					t.Errorf("%s: got %v, want append-only error", query, err)
+				}
+			}
+
+			entries, err := store.Audit.List(ctx, entity.AuditFilter{Limit: 10})
+			if err != nil {
+				t.Fatalf("list entries: %v", err)
+			}
+			if len(entries) != 1 || entries[0].Actor != "test" {
+				t.Errorf("entries after rejected changes: %+v", entries)
+			}
+		})
+	}
+}
@@ -1,32 +0,0 @@
-package storage
-
-import (
-	"context"
-	_ "embed"
-	"fmt"
-
-	"github.com/jackc/pgx/v5/pgxpool"
-)
-
-//go:embed init.sql
-var initSQL string
-
-//go:embed data.sql
-var dataSQL string
-
-func Migrate(db *pgxpool.Pool) error {
-	_, err := db.Exec(context.Background(), initSQL)
-	if err != nil {
-		return fmt.Errorf("init sql failed: %w", err)
-	}
-
-	return nil
-}
-
-func DataInsert(db *pgxpool.Pool) error {
-	_, err := db.Exec(context.Background(), dataSQL)
-	if err != nil {
-		return fmt.Errorf("data insert failed: %w", err)
-	}
-	return nil
-}
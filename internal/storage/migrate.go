@@ -0,0 +1,374 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations
+var migrationsFS embed.FS
+
+// migrationLockKey - ключ advisory lock, под которым реплики по очереди применяют миграции.
+const migrationLockKey = 7423001
+
+// Migration - пронумерованная пара скриптов up/down из migrations/<driver>.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus - миграция и время ее применения (nil - еще не применена).
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator применяет миграции и учитывает их в таблице schema_migrations.
+type Migrator struct {
+	driver     migrationDriver
+	migrations []Migration
+}
+
+// migrationDriver захватывает блокировку миграций и возвращает сессию, в которой она держится.
+type migrationDriver interface {
+	lock(ctx context.Context) (migrationSession, error)
+}
+
+type migrationSession interface {
+	ensureTable(ctx context.Context) error
+	applied(ctx context.Context) (map[int]time.Time, error)
+	// apply выполняет скрипт и отмечает миграцию в одной транзакции
+	apply(ctx context.Context, m Migration, up bool) error
+	unlock(ctx context.Context)
+}
+
+func NewPostgresMigrator(db *pgxpool.Pool) (*Migrator, error) {
+	return newMigrator(DriverPostgres, &pgMigrationDriver{db: db})
+}
+
+func NewSQLiteMigrator(db *sql.DB) (*Migrator, error) {
+	return newMigrator(DriverSQLite, &sqliteMigrationDriver{db: db})
+}
+
+func newMigrator(dir string, driver migrationDriver) (*Migrator, error) {
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{driver: driver, migrations: migrations}, nil
+}
+
+// loadMigrations читает файлы вида 0001_name.up.sql / 0001_name.down.sql.
+func loadMigrations(dir string) ([]Migration, error) {
+	root := path.Join("migrations", dir)
+	entries, err := fs.ReadDir(migrationsFS, root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		name := entry.Name()
+
+		var up bool
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			up = true
+		case strings.HasSuffix(name, ".down.sql"):
+		default:
+			continue
+		}
+
+		prefix, title, ok := strings.Cut(strings.TrimSuffix(strings.TrimSuffix(name, ".up.sql"), ".down.sql"), "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name: %s", name)
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version: %s", name)
+		}
+
+		content, err := fs.ReadFile(migrationsFS, path.Join(root, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", name, err)
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: title}
+			byVersion[version] = m
+		}
+		if up {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return a.Version - b.Version
+	})
+
+	return migrations, nil
+}
+
+// Up применяет все непримененные миграции по возрастанию версии.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.withSession(ctx, func(session migrationSession, applied map[int]time.Time) error {
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := session.apply(ctx, migration, true); err != nil {
+				return fmt.Errorf("migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// Down откатывает steps последних примененных миграций.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.withSession(ctx, func(session migrationSession, applied map[int]time.Time) error {
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %04d_%s has no down script", migration.Version, migration.Name)
+			}
+			if err := session.apply(ctx, migration, false); err != nil {
+				return fmt.Errorf("rollback of %04d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// Status возвращает все известные миграции с отметкой о применении.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.withSession(ctx, func(_ migrationSession, applied map[int]time.Time) error {
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Migration: migration}
+			if appliedAt, ok := applied[migration.Version]; ok {
+				status.AppliedAt = &appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+
+	return statuses, err
+}
+
+func (m *Migrator) withSession(ctx context.Context, fn func(session migrationSession, applied map[int]time.Time) error) error {
+	session, err := m.driver.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer session.unlock(ctx)
+
+	if err := session.ensureTable(ctx); err != nil {
+		return err
+	}
+
+	applied, err := session.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	return fn(session, applied)
+}
+
+// pgMigrationDriver держит advisory lock на отдельном соединении пула,
+// поэтому реплики, стартующие одновременно, применяют миграции по очереди.
+type pgMigrationDriver struct {
+	db *pgxpool.Pool
+}
+
+type pgMigrationSession struct {
+	conn *pgxpool.Conn
+}
+
+func (d *pgMigrationDriver) lock(ctx context.Context) (migrationSession, error) {
+	conn, err := d.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		conn.Release()
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	return &pgMigrationSession{conn: conn}, nil
+}
+
+func (s *pgMigrationSession) ensureTable(ctx context.Context) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`
+
+	if _, err := s.conn.Exec(ctx, query); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return nil
+}
+
+func (s *pgMigrationSession) applied(ctx context.Context) (map[int]time.Time, error) {
+	rows, err := s.conn.Query(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+func (s *pgMigrationSession) apply(ctx context.Context, m Migration, up bool) error {
+	return pgx.BeginFunc(ctx, s.conn, func(tx pgx.Tx) error {
+		script := m.Up
+		if !up {
+			script = m.Down
+		}
+		if _, err := tx.Exec(ctx, script); err != nil {
+			return err
+		}
+
+		if up {
+			_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name)
+			return err
+		}
+		_, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.Version)
+		return err
+	})
+}
+
+func (s *pgMigrationSession) unlock(ctx context.Context) {
+	_, _ = s.conn.Exec(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+	s.conn.Release()
+}
+
+// sqliteMigrationDriver не нуждается в отдельной блокировке: каждая миграция
+// выполняется в транзакции BEGIN IMMEDIATE, а писатель в SQLite всегда один.
+type sqliteMigrationDriver struct {
+	db *sql.DB
+}
+
+func (d *sqliteMigrationDriver) lock(_ context.Context) (migrationSession, error) {
+	return d, nil
+}
+
+func (d *sqliteMigrationDriver) ensureTable(ctx context.Context) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+		)
+	`
+
+	if _, err := d.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return nil
+}
+
+func (d *sqliteMigrationDriver) applied(ctx context.Context) (map[int]time.Time, error) {
+	rows, err := d.db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+func (d *sqliteMigrationDriver) apply(ctx context.Context, m Migration, up bool) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	script := m.Up
+	if !up {
+		script = m.Down
+	}
+	if _, err = tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+
+	if up {
+		_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES (?1, ?2)`, m.Version, m.Name)
+	} else {
+		_, err = tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?1`, m.Version)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (d *sqliteMigrationDriver) unlock(_ context.Context) {}
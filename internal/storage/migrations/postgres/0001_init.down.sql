@@ -0,0 +1,8 @@
+-- Удаление исходной схемы (в порядке, обратном зависимостям)
+DROP TABLE IF EXISTS pr_reviewers;
+DROP TABLE IF EXISTS pull_requests;
+DROP TABLE IF EXISTS team_fallbacks;
+DROP TABLE IF EXISTS team_settings;
+DROP TABLE IF EXISTS team_members;
+DROP TABLE IF EXISTS teams;
+DROP TABLE IF EXISTS users;
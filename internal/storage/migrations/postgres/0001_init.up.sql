@@ -0,0 +1,119 @@
+-- Исходная схема. Идемпотентна: базы, созданные до появления миграций, принимают ее без ошибок
+
+-- Таблица пользователей
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(255) NOT NULL UNIQUE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- Таблица команд
+CREATE TABLE IF NOT EXISTS teams (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- Таблица связи пользователей и команд (многие ко многим)
+CREATE TABLE IF NOT EXISTS team_members (
+    id SERIAL PRIMARY KEY,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(team_id, user_id)
+    );
+
+-- Вес участника при взвешенном выборе ревьюверов
+ALTER TABLE team_members ADD COLUMN IF NOT EXISTS review_weight INTEGER NOT NULL DEFAULT 1;
+
+-- Основная команда пользователя (приоритетна при выборе команды PR)
+ALTER TABLE team_members ADD COLUMN IF NOT EXISTS is_primary BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Настройки команды
+CREATE TABLE IF NOT EXISTS team_settings (
+    team_id INTEGER PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+    reviewer_strategy VARCHAR(20) NOT NULL DEFAULT 'random',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- Количество ревьюверов на PR и поведение при нехватке кандидатов
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS reviewers_per_pr INTEGER NOT NULL DEFAULT 2;
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS min_reviewers INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS fail_on_insufficient BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Сколько одобрений нужно для merge (0 - merge не блокируется)
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS required_approvals INTEGER NOT NULL DEFAULT 0;
+
+-- Резервные команды, из которых добираются ревьюверы при нехватке кандидатов
+CREATE TABLE IF NOT EXISTS team_fallbacks (
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    fallback_team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    priority INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (team_id, fallback_team_id),
+    CHECK (team_id <> fallback_team_id)
+    );
+
+-- Таблица Pull Request'ов
+CREATE TABLE IF NOT EXISTS pull_requests (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(100) NOT NULL,
+    author_id INTEGER NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('DRAFT', 'OPEN', 'MERGED', 'CLOSED')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    merged_at TIMESTAMP
+    );
+
+-- Полный жизненный цикл PR: DRAFT -> OPEN -> MERGED/CLOSED, CLOSED -> OPEN
+ALTER TABLE pull_requests DROP CONSTRAINT IF EXISTS pull_requests_status_check;
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_status_check CHECK (status IN ('DRAFT', 'OPEN', 'MERGED', 'CLOSED'));
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP;
+
+-- Команда, для которой создан PR
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS team_id INTEGER REFERENCES teams(id) ON DELETE SET NULL;
+
+-- PR создан с числом ревьюверов меньше минимума команды
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS degraded BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Таблица назначенных ревьюверов на PR
+CREATE TABLE IF NOT EXISTS pr_reviewers (
+    id SERIAL PRIMARY KEY,
+    pr_id INTEGER NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    reviewer_id INTEGER NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    assigned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(pr_id, reviewer_id)
+    );
+
+-- Команда, из которой назначен ревьювер
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS team_id INTEGER REFERENCES teams(id) ON DELETE SET NULL;
+
+-- Ревьювер назначен из резервной команды
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS cross_team BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Вердикт ревьювера и время его отправки
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS verdict VARCHAR(20) CHECK (verdict IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED'));
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
+
+
+
+-- Индекс для быстрого поиска PR по автору
+CREATE INDEX IF NOT EXISTS idx_pr_author ON pull_requests(author_id);
+
+-- Индекс для быстрого поиска PR по статусу
+CREATE INDEX IF NOT EXISTS idx_pr_status ON pull_requests(status);
+
+-- Индекс для быстрого поиска ревьюверов по PR
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_pr ON pr_reviewers(pr_id);
+
+-- Индекс для быстрого поиска PR по ревьюверу
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_reviewer ON pr_reviewers(reviewer_id);
+
+-- Индекс для быстрого поиска участников команды
+CREATE INDEX IF NOT EXISTS idx_team_members_team ON team_members(team_id);
+CREATE INDEX IF NOT EXISTS idx_team_members_user ON team_members(user_id);
+
+-- Индекс для быстрого поиска активных пользователей
+CREATE INDEX IF NOT EXISTS idx_users_active ON users(is_active) WHERE is_active = TRUE;
@@ -0,0 +1,11 @@
+-- API-токены. Хранится только SHA-256 хеш, сам токен показывается один раз при выпуске
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('admin', 'team-lead', 'member', 'bot')),
+    -- Пользователь, от имени которого действует токен; у ботов может отсутствовать
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP
+    );
@@ -0,0 +1,9 @@
+-- Связь PR во внешней системе (GitHub, GitLab) с PR сервиса
+CREATE TABLE IF NOT EXISTS external_pull_requests (
+    provider VARCHAR(20) NOT NULL,
+    repository VARCHAR(255) NOT NULL,
+    number INTEGER NOT NULL,
+    pr_id INTEGER NOT NULL UNIQUE REFERENCES pull_requests(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, repository, number)
+    );
@@ -0,0 +1,21 @@
+-- Журнал изменений назначений, статусов и состава команд. Строки только добавляются
+CREATE TABLE IF NOT EXISTS audit_log (
+    id SERIAL PRIMARY KEY,
+    -- Кто выполнил действие: пользователь, токен, внешняя система
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    -- Без внешних ключей: запись должна пережить удаление PR, пользователя или команды
+    pr_id INTEGER,
+    user_id INTEGER,
+    team_id INTEGER,
+    before_state JSONB,
+    after_state JSONB,
+    -- Почему выбран ревьювер или снято назначение
+    reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_pr ON audit_log(pr_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_user ON audit_log(user_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_team ON audit_log(team_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_outbox_events_due;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_due ON outbox_events(next_attempt_at) WHERE processed_at IS NULL;
+
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS failed_at;
@@ -0,0 +1,2 @@
+-- Положение последовательности не откатывается: выданные id уже заняты PR
+SELECT 1;
@@ -0,0 +1,2 @@
+-- PR без своего id (из вебхуков) получают id из pull_requests_id_seq: сдвигаем ее за id, уже выбранные клиентами
+SELECT setval('pull_requests_id_seq', COALESCE(MAX(id), 0) + 1, false) FROM pull_requests;
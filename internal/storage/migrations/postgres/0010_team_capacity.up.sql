@@ -0,0 +1,2 @@
+-- Предел открытых ревью на одного ревьювера, 0 - без ограничения
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS max_open_reviews INTEGER NOT NULL DEFAULT 0;
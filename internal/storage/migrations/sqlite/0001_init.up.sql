@@ -0,0 +1,96 @@
+-- Схема SQLite, эквивалентная миграциям Postgres.
+-- Время хранится в UTC с миллисекундами, чтобы порядок назначений не зависел от совпадения секунд
+
+-- Таблица пользователей
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username VARCHAR(255) NOT NULL UNIQUE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    updated_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+    );
+
+-- Таблица команд
+CREATE TABLE IF NOT EXISTS teams (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    updated_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+    );
+
+-- Таблица связи пользователей и команд (многие ко многим)
+CREATE TABLE IF NOT EXISTS team_members (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    review_weight INTEGER NOT NULL DEFAULT 1,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    UNIQUE(team_id, user_id)
+    );
+
+-- Настройки команды
+CREATE TABLE IF NOT EXISTS team_settings (
+    team_id INTEGER PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+    reviewer_strategy VARCHAR(20) NOT NULL DEFAULT 'random',
+    reviewers_per_pr INTEGER NOT NULL DEFAULT 2,
+    min_reviewers INTEGER NOT NULL DEFAULT 0,
+    fail_on_insufficient BOOLEAN NOT NULL DEFAULT FALSE,
+    required_approvals INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+    );
+
+-- Резервные команды, из которых добираются ревьюверы при нехватке кандидатов
+CREATE TABLE IF NOT EXISTS team_fallbacks (
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    fallback_team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    priority INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (team_id, fallback_team_id),
+    CHECK (team_id <> fallback_team_id)
+    );
+
+-- Таблица Pull Request'ов
+CREATE TABLE IF NOT EXISTS pull_requests (
+    id INTEGER PRIMARY KEY,
+    title VARCHAR(100) NOT NULL,
+    author_id INTEGER NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    team_id INTEGER REFERENCES teams(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('DRAFT', 'OPEN', 'MERGED', 'CLOSED')),
+    degraded BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    updated_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    merged_at TIMESTAMP,
+    closed_at TIMESTAMP
+    );
+
+-- Таблица назначенных ревьюверов на PR
+CREATE TABLE IF NOT EXISTS pr_reviewers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pr_id INTEGER NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    reviewer_id INTEGER NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    team_id INTEGER REFERENCES teams(id) ON DELETE SET NULL,
+    cross_team BOOLEAN NOT NULL DEFAULT FALSE,
+    verdict VARCHAR(20) CHECK (verdict IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    reviewed_at TIMESTAMP,
+    assigned_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    UNIQUE(pr_id, reviewer_id)
+    );
+
+-- Индекс для быстрого поиска PR по автору
+CREATE INDEX IF NOT EXISTS idx_pr_author ON pull_requests(author_id);
+
+-- Индекс для быстрого поиска PR по статусу
+CREATE INDEX IF NOT EXISTS idx_pr_status ON pull_requests(status);
+
+-- Индекс для быстрого поиска ревьюверов по PR
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_pr ON pr_reviewers(pr_id);
+
+-- Индекс для быстрого поиска PR по ревьюверу
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_reviewer ON pr_reviewers(reviewer_id);
+
+-- Индекс для быстрого поиска участников команды
+CREATE INDEX IF NOT EXISTS idx_team_members_team ON team_members(team_id);
+CREATE INDEX IF NOT EXISTS idx_team_members_user ON team_members(user_id);
+
+-- Индекс для быстрого поиска активных пользователей
+CREATE INDEX IF NOT EXISTS idx_users_active ON users(is_active) WHERE is_active = TRUE;
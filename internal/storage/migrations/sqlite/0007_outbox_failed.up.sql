@@ -0,0 +1,5 @@
+-- Событие, не принятое приемниками за maxOutboxAttempts попыток, больше не повторяется
+ALTER TABLE outbox_events ADD COLUMN failed_at TIMESTAMP;
+
+DROP INDEX IF EXISTS idx_outbox_events_due;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_due ON outbox_events(next_attempt_at) WHERE processed_at IS NULL AND failed_at IS NULL;
@@ -0,0 +1,3 @@
+-- SQLite выдает id PR без своего id через INTEGER PRIMARY KEY, последовательность не нужна.
+-- Миграция сохраняет общую нумерацию с PostgreSQL
+SELECT 1;
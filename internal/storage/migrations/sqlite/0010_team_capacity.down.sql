@@ -0,0 +1 @@
+ALTER TABLE team_settings DROP COLUMN max_open_reviews;
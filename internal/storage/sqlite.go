@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"PR-appointer/config"
+)
+
+// NewSQLiteConnection открывает файл базы SQLite.
+// Транзакции берут блокировку на запись сразу (BEGIN IMMEDIATE), чтобы
+// конкурирующие изменения одного PR выполнялись по очереди.
+func NewSQLiteConnection(ctx context.Context, cfg *config.Config) (*sql.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000&_txlock=immediate", cfg.Env.DBPath)
+
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open sqlite database: %w", err)
+	}
+
+	// SQLite допускает одного писателя, а :memory: живет в рамках одного соединения
+	db.SetMaxOpenConns(1)
+
+	if err = db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("unable to connect to sqlite database: %w", err)
+	}
+
+	slog.Info("connected to sqlite database", "path", cfg.Env.DBPath)
+
+	return db, nil
+}
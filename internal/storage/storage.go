@@ -39,15 +39,6 @@ func NewConnection(ctx context.Context, cfg *config.Config) *pgxpool.Pool {
 		panic(err)
 	}
 
-	if err = Migrate(conn); err != nil {
-		slog.Error("Unable to migrate database:", err.Error(), nil)
-		panic(err)
-	}
-	if err = DataInsert(conn); err != nil {
-		slog.Error("Unable to migrate data:", err.Error(), nil)
-		panic(err)
-	}
-
 	slog.Info("Connected to database")
 
 	return conn
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"PR-appointer/config"
+	"PR-appointer/internal/repository"
+)
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+// Демонстрационные пользователи и команды; загружаются только по запросу
+//
+//go:embed seed.sql
+var seedSQL string
+
+// Database - подключение к хранилищу, выбранному в DB_DRIVER.
+type Database struct {
+	Store *repository.Store
+	// Migrator равен nil для хранилища в памяти
+	Migrator *Migrator
+
+	seed  func(ctx context.Context) error
+	close func()
+}
+
+// Open подключается к хранилищу, не применяя миграции.
+func Open(ctx context.Context, cfg *config.Config) (*Database, error) {
+	switch cfg.Env.DBDriver {
+	case DriverPostgres, "":
+		pool := NewConnection(ctx, cfg)
+		cfg.Client = pool
+
+		migrator, err := NewPostgresMigrator(pool)
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+
+		return &Database{
+			Store:    repository.NewPostgresStore(pool),
+			Migrator: migrator,
+			seed: func(ctx context.Context) error {
+				return seedPostgres(ctx, pool)
+			},
+			close: pool.Close,
+		}, nil
+	case DriverSQLite:
+		db, err := NewSQLiteConnection(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		migrator, err := NewSQLiteMigrator(db)
+		if err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+
+		return &Database{
+			Store:    repository.NewSQLiteStore(db),
+			Migrator: migrator,
+			seed: func(ctx context.Context) error {
+				return seedSQLite(ctx, db)
+			},
+			close: func() {
+				_ = db.Close()
+			},
+		}, nil
+	case DriverMemory:
+		slog.Warn("using in-memory storage, data will be lost on restart")
+		return &Database{
+			Store: repository.NewMemoryStore(),
+			seed: func(context.Context) error {
+				return errors.New("seed is not supported for in-memory storage")
+			},
+			close: func() {},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown DB_DRIVER: %s", cfg.Env.DBDriver)
+	}
+}
+
+// NewStore подключается к хранилищу, применяет новые миграции и,
+// если задан SEED_DATA, загружает демонстрационные данные.
+func NewStore(ctx context.Context, cfg *config.Config) (*repository.Store, error) {
+	db, err := Open(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if db.Migrator != nil {
+		applied, err := db.Migrator.Up(ctx)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		for _, m := range applied {
+			slog.Info("migration applied", "version", m.Version, "name", m.Name)
+		}
+	}
+
+	if cfg.Env.SeedData {
+		if err := db.Seed(ctx); err != nil {
+			db.Close()
+			return nil, err
+		}
+		slog.Info("seed data loaded")
+	}
+
+	return db.Store, nil
+}
+
+// Seed загружает демонстрационные данные; повторный запуск ничего не дублирует.
+func (d *Database) Seed(ctx context.Context) error {
+	return d.seed(ctx)
+}
+
+func (d *Database) Close() {
+	d.close()
+}
+
+func seedPostgres(ctx context.Context, db *pgxpool.Pool) error {
+	_, err := db.Exec(ctx, seedSQL)
+	if err != nil {
+		return fmt.Errorf("data insert failed: %w", err)
+	}
+	return nil
+}
+
+func seedSQLite(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, seedSQL)
+	if err != nil {
+		return fmt.Errorf("data insert failed: %w", err)
+	}
+	return nil
+}
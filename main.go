@@ -2,8 +2,12 @@ package main
 
 import (
 	"PR-appointer/cmd/app"
+	"PR-appointer/cmd/cli"
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"os"
 	"os/signal"
 	"syscall"
 
@@ -27,9 +31,14 @@ import (
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
-// @description Type "Bearer" followed by a space and JWT token.
+// @description Type "Bearer" followed by a space and an API token or an SSO JWT.
 
 func main() {
+	// Без аргументов и с "serve" запускается HTTP-сервер, остальное - служебные команды
+	if len(os.Args) > 1 && os.Args[1] != "serve" {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
 	slog.Info("Starting main")
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
@@ -59,3 +68,21 @@ func main() {
 
 	slog.Info("Shutdown completed")
 }
+
+func runCommand(args []string) int {
+	// stdout занят результатом команды, логи пишем в stderr
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := cli.Run(ctx, args, os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		if errors.Is(err, cli.ErrUsage) {
+			return 2
+		}
+		return 1
+	}
+
+	return 0
+}